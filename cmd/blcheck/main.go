@@ -4,29 +4,115 @@ blcheck - A simple tool to check which links on your websites are broken.
 package main
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
 	"time"
 
 	args "github.com/Felixs/blcheck/pkg/arguments" // handels flag parsing on init
 	"github.com/Felixs/blcheck/pkg/constants"
+	"github.com/Felixs/blcheck/pkg/server"
 	"github.com/Felixs/blcheck/pkg/url"
 )
 
+// Progress and status messages, written to stderr so stdout only gets the report
+var infoOutput io.Writer = os.Stderr
+
+// Previous report given by -baseline
+var baselineReport *url.UrlReport
+
+// Removed urls per filter rule of the current check
+var linkFilterStats, crawlFilterStats url.FilterStats
+
+// Rules for known broken urls, from -ignore-file or .blcheckignore
+var ignoreRules url.IgnoreRules
+
+// Set if the crawl was stopped by a signal, the report only contains the urls checked until then
+var crawlStopped bool
+
+// Results of previous runs given by -cache, nil if results are not cached
+var resultCache *url.ResultCache
+
+// Changes since baselineReport, set after the check if a baseline is given
+var baselineDiff *url.ReportDiff
+
+// Writer of streamed NDJSON output, set if output is streamed
+var ndjsonStream *url.NdjsonWriter
+
+// Output file of streamed NDJSON output, nil if streamed to stdout
+var ndjsonFile *os.File
+
+// Error of a check run, that ends blcheck with exit code.
+type checkError struct {
+	err      error
+	message  string
+	exitCode int
+}
+
+func (e checkError) Error() string {
+	return fmt.Sprintf("%v\nERROR: %s", e.err, e.message)
+}
+
 // Blcheck entry point.
 func main() {
 	args.Parse()
-	parseStart := time.Now()
-	httpUrls, err := extractURLs(args.URL)
-	if err != nil {
-		fmt.Printf("%v\nERROR: Failure to extract links from given URL.\n", err)
-		os.Exit(constants.ExitUrlNotReachable)
+	if args.Command == args.CommandDiff {
+		diffReports()
+		return
 	}
-	parsingDuration := time.Since(parseStart)
 
-	// create reports for all http urls
-	urlReports := createUrlReport(httpUrls)
-	urlReports.AddMetaData("initial_parsing_duration", parsingDuration.String())
+	if err := loadIgnoreRules(); err != nil {
+		fmt.Printf("%v\nERROR: Failure to read ignore file.\n", err)
+		os.Exit(constants.ExitFailedToReadInput)
+	}
+	url.SetProgressIgnoreRules(ignoreRules)
+
+	if args.CacheFile != "" {
+		cache, err := url.LoadResultCache(args.CacheFile, args.CacheTTL, args.CacheBrokenTTL)
+		if err != nil {
+			fmt.Printf("%v\nERROR: Failure to read cache file.\n", err)
+			os.Exit(constants.ExitFailedToReadInput)
+		}
+		resultCache = cache
+		url.SetResultCache(cache)
+	}
+
+	if args.Command == args.CommandServe {
+		serveReports()
+		return
+	}
+
+	if args.BaselineFile != "" {
+		baseline, err := url.ReadJsonReport(args.BaselineFile)
+		if err != nil {
+			fmt.Printf("%v\nERROR: Failure to read baseline report.\n", err)
+			os.Exit(constants.ExitFailedToReadInput)
+		}
+		baselineReport = &baseline
+	}
+
+	if args.OutputAsNDJSON {
+		if err := startNdjsonStream(); err != nil {
+			fmt.Printf("Failure to open output. ERROR: %v\n", err)
+			os.Exit(constants.ExitFailedToWriteReport)
+		}
+	}
+
+	urlReports, err := runCheck()
+	if err != nil {
+		fmt.Println(err.Error())
+		var ce checkError
+		if errors.As(err, &ce) {
+			os.Exit(ce.exitCode)
+		}
+		os.Exit(constants.ExitFailedToCreateReport)
+	}
 
 	// creating report in desired output and format
 	err = deliverReport(urlReports)
@@ -34,25 +120,314 @@ func main() {
 		fmt.Printf("Failure to deliver output. ERROR: %v", err)
 		os.Exit(constants.ExitFailedToWriteReport)
 	}
-	fmt.Println(args.GoodbyMsg)
+	fmt.Fprintln(infoOutput, args.GoodbyMsg)
 
-	// descide on exit code
+	if crawlStopped {
+		os.Exit(constants.ExitCrawlStopped)
+	}
+	// descide on exit code, with a baseline only newly broken urls count
+	if baselineDiff != nil {
+		if baselineDiff.HasNewlyBroken() {
+			os.Exit(constants.ExitNotAllReportReachable)
+		}
+		return
+	}
 	if !urlReports.AllReachable() {
 		os.Exit(constants.ExitNotAllReportReachable)
 	}
 }
 
+// Reads ignore rules from -ignore-file or from .blcheckignore if it exists and adds the ignore list of the config file,
+// warns about expired rules.
+func loadIgnoreRules() error {
+	args.ConfigIgnoreRules.WarnExpired(infoOutput, "ignore list of "+args.ConfigFile, time.Now())
+	ignoreRules = args.ConfigIgnoreRules
+	path := args.IgnoreFile
+	if path == "" {
+		if _, err := os.Stat(url.DefaultIgnoreFile); err != nil {
+			return nil
+		}
+		path = url.DefaultIgnoreFile
+	}
+	rules, err := url.ReadIgnoreFile(path)
+	if err != nil {
+		return err
+	}
+	rules.WarnExpired(infoOutput, path, time.Now())
+	ignoreRules = append(rules, args.ConfigIgnoreRules...)
+	return nil
+}
+
+// Compares the two reports given to the diff command and exits with error if urls are newly broken.
+func diffReports() {
+	baseline, err := url.ReadJsonReport(args.DiffBaselineFile)
+	if err != nil {
+		fmt.Printf("%v\nERROR: Failure to read baseline report.\n", err)
+		os.Exit(constants.ExitFailedToReadInput)
+	}
+	current, err := url.ReadJsonReport(args.DiffCurrentFile)
+	if err != nil {
+		fmt.Printf("%v\nERROR: Failure to read current report.\n", err)
+		os.Exit(constants.ExitFailedToReadInput)
+	}
+
+	diff := url.DiffReports(baseline, current)
+	output := diff.String()
+	if args.OutputAsJSON {
+		output, err = diff.Json()
+		if err != nil {
+			fmt.Println("Error in diff output creation: " + err.Error())
+			os.Exit(constants.ExitFailedToCreateReport)
+		}
+	}
+	fmt.Println(output)
+	if diff.HasNewlyBroken() {
+		os.Exit(constants.ExitNotAllReportReachable)
+	}
+}
+
+// Shows progress of the url checks on stderr until the returned func is called.
+func startProgress() (stop func()) {
+	reporter := url.NewProgressReporter(os.Stderr, isTerminal(os.Stderr), args.ProgressInterval)
+	previousInfoOutput := infoOutput
+	infoOutput = reporter.Above(infoOutput)
+	reporter.Start()
+	return func() {
+		reporter.Stop()
+		infoOutput = previousInfoOutput
+	}
+}
+
+// Checks if file is a terminal.
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Checks urls of all given pages and directories, or of all crawled pages for the crawl command.
+func runCheck() (url.UrlReport, error) {
+	linkFilterStats = args.LinkFilter.NewStats()
+	crawlFilterStats = args.CrawlFilter.NewStats()
+	if args.Command != args.CommandServe && args.ProgressInterval > 0 && !args.ExecuteDryRun {
+		defer startProgress()()
+	}
+	var urlReports url.UrlReport
+	var err error
+	if args.Command == args.CommandCrawl && !args.ExecuteDryRun {
+		urlReports, err = crawlPages()
+	} else {
+		urlReports, err = checkPagesAndFiles()
+	}
+	if err != nil {
+		return url.UrlReport{}, err
+	}
+
+	if len(args.URLs) > 0 {
+		urlReports.AddMetaData("checked_pages", fmt.Sprint(len(args.URLs)))
+		urlReports.AddMetaData("checked_urls", strings.Join(args.URLs, " "))
+	}
+	if args.LocalDirectory != "" {
+		urlReports.AddMetaData("checked_directory", args.LocalDirectory)
+	}
+	if !args.LinkFilter.IsEmpty() {
+		urlReports.AddMetaData("filter_removed_urls", linkFilterStats.String())
+	}
+	if !args.CrawlFilter.IsEmpty() {
+		urlReports.AddMetaData("crawl_filter_removed_pages", crawlFilterStats.String())
+	}
+	if resultCache != nil {
+		if err := resultCache.Save(); err != nil {
+			fmt.Fprintf(infoOutput, "WARNING: Failure to write cache file: %v\n", err)
+		}
+		hits, revalidated := resultCache.Stats()
+		urlReports.AddMetaData("cache_hits", fmt.Sprint(hits))
+		urlReports.AddMetaData("cache_revalidated", fmt.Sprint(revalidated))
+	}
+	addEnvironmentMetaData(urlReports)
+	return urlReports, nil
+}
+
+// Crawls all pages on the hosts of the given pages and checks every url found on them.
+func crawlPages() (url.UrlReport, error) {
+	crawler := url.Crawler{
+		MaxDepth:            args.MaxCrawlDepth,
+		MaxPages:            args.MaxCrawlPages,
+		MaxParallelRequests: args.MaxParallelRequests,
+		DescendInto:         args.DescendInto,
+		LinkFilter:          &linkFilterStats,
+		CrawlFilter:         &crawlFilterStats,
+		InfoOutput:          infoOutput,
+		StatePath:           cmp.Or(args.CrawlStateFile, args.CrawlResumeFile),
+	}
+	stop, release := stopOnSignal()
+	defer release()
+	crawler.Stop = stop
+	var urlReports url.UrlReport
+	var err error
+	if args.CrawlResumeState != nil {
+		urlReports, err = crawler.Resume(*args.CrawlResumeState)
+	} else {
+		urlReports, err = crawler.Crawl(args.URLs)
+	}
+	if errors.Is(err, url.ErrCrawlStopped) {
+		crawlStopped = true
+		if crawler.StatePath != "" {
+			fmt.Fprintf(infoOutput, "WARNING: Crawl stopped, the report is incomplete. Continue with: blcheck crawl -resume %s\n", crawler.StatePath)
+		} else {
+			fmt.Fprintln(infoOutput, "WARNING: Crawl stopped, the report is incomplete. Use -state to be able to continue a crawl.")
+		}
+	} else if err != nil {
+		return url.UrlReport{}, checkError{err, "Failure to crawl given URL.", constants.ExitUrlNotReachable}
+	}
+	return completeUrlReport(urlReports, len(urlReports.UrlStatus)), nil
+}
+
+// Returns chan that is closed on the first SIGINT or SIGTERM, a second signal ends blcheck immediately.
+// Signals are handled as usual again after release is called.
+func stopOnSignal() (stop <-chan struct{}, release func()) {
+	stopChan := make(chan struct{})
+	released := make(chan struct{})
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case sig := <-signals:
+			signal.Stop(signals)
+			fmt.Fprintf(os.Stderr, "Received %v, stopping crawl after the current urls. Repeat to quit immediately.\n", sig)
+			close(stopChan)
+		case <-released:
+		}
+	}()
+	return stopChan, func() {
+		signal.Stop(signals)
+		close(released)
+	}
+}
+
+// Extracts urls from all given pages and directories and checks them.
+func checkPagesAndFiles() (url.UrlReport, error) {
+	parseStart := time.Now()
+	httpUrls := []url.ExtractedUrl{}
+	if len(args.URLs) > 0 {
+		pageUrls, err := extractURLsFromPages(args.URLs)
+		if err != nil {
+			return url.UrlReport{}, checkError{err, "Failure to extract links from given URL.", constants.ExitUrlNotReachable}
+		}
+		httpUrls = append(httpUrls, pageUrls...)
+	}
+	if args.LocalDirectory != "" {
+		fileUrls, err := extractURLsFromLocalSite(args.LocalDirectory, args.LocalBaseUrl)
+		if err != nil {
+			return url.UrlReport{}, checkError{err, "Failure to extract links from given directory.", constants.ExitFailedToReadInput}
+		}
+		httpUrls = url.MergeExtractedUrls(append(httpUrls, fileUrls...))
+	}
+	httpUrls = filterURLs(httpUrls)
+	parsingDuration := time.Since(parseStart)
+
+	// create reports for all http urls
+	urlReports := createUrlReport(httpUrls)
+	urlReports.AddMetaData("initial_parsing_duration", parsingDuration.String())
+	return urlReports, nil
+}
+
+// Adds information about blcheck and where it was run, so stored reports describe themselves.
+func addEnvironmentMetaData(urlReports url.UrlReport) {
+	urlReports.AddMetaData("blcheck_version", args.Version)
+	urlReports.AddMetaData("configuration", args.EffectiveConfiguration())
+	if hostName, err := os.Hostname(); err == nil {
+		urlReports.AddMetaData("host_name", hostName)
+	}
+}
+
+// Runs webserver to browse reports in report directory, checks can be rerun from the browser.
+func serveReports() {
+	var rerun server.RunFunc
+	if len(args.URLs) > 0 || args.LocalDirectory != "" {
+		rerun = runCheck
+	}
+	fmt.Printf("Serving reports of %s on http://%s\n", args.ReportDirectory, args.ServeAddress)
+	err := server.New(args.ReportDirectory, rerun).ListenAndServe(args.ServeAddress)
+	if err != nil {
+		fmt.Printf("Failure to serve reports. ERROR: %v\n", err)
+		os.Exit(constants.ExitFailedToServe)
+	}
+}
+
+// Streams every checked url as NDJSON line to stdout or the output file while checks are running.
+// Lines have ignore rules applied, but are not compared with the baseline.
+func startNdjsonStream() error {
+	var output io.Writer = os.Stdout
+	if args.OutputInFile != "" {
+		file, err := os.Create(args.OutputInFile)
+		if err != nil {
+			return err
+		}
+		ndjsonFile = file
+		output = file
+	}
+	ndjsonStream = url.NewNdjsonWriter(output, args.ShowReachables)
+	url.AddResultListener(func(s url.UrlStatus) {
+		if err := ndjsonStream.WriteStatus(ignoreRules.Apply(s, time.Now())); err != nil {
+			fmt.Fprintf(os.Stderr, "WARNING: Failure to stream result of %s: %v\n", s.Url, err)
+		}
+	})
+	return nil
+}
+
+// Finishes streamed NDJSON output with the summary line, dry runs are not streamed and get written completely.
+func deliverNdjsonSummary(urlReports url.UrlReport) error {
+	url.ClearResultListeners()
+	if args.ExecuteDryRun {
+		for _, s := range urlReports.UrlStatus {
+			if err := ndjsonStream.WriteStatus(s); err != nil {
+				return err
+			}
+		}
+	}
+	if err := ndjsonStream.WriteSummary(urlReports); err != nil {
+		return err
+	}
+	if ndjsonFile != nil {
+		return ndjsonFile.Close()
+	}
+	return nil
+}
+
 // Delivers UrlReport as desired format
 func deliverReport(urlReports url.UrlReport) error {
+	if ndjsonStream != nil {
+		if err := deliverNdjsonSummary(urlReports); err != nil {
+			return err
+		}
+		return storeReportFiles(urlReports)
+	}
+
 	var reportOutput string
 	var err error
 	switch {
 	case args.OutputAsJSON:
 		reportOutput, err = urlReports.Json()
+	case args.OutputAsCSV && args.OutputCsvMetaData:
+		reportOutput, err = urlReports.CsvWithMetaData(true)
 	case args.OutputAsCSV:
 		reportOutput, err = urlReports.Csv(true)
+	case args.OutputAsHTML:
+		reportOutput, err = urlReports.Html()
+	case args.OutputAsJUnit:
+		reportOutput, err = urlReports.JUnit()
+	case args.OutputAsSarif:
+		reportOutput, err = urlReports.Sarif()
+	case args.OutputAsMarkdown:
+		reportOutput = urlReports.Markdown()
+	case args.OutputAsGithub:
+		reportOutput = urlReports.GithubAnnotations()
+		err = urlReports.WriteGithubStepSummary()
 	default:
 		reportOutput = urlReports.FullString()
+		if baselineDiff != nil {
+			reportOutput += "\n" + baselineDiff.String()
+		}
 	}
 	if err != nil {
 		fmt.Println("Error in report output creation: " + err.Error())
@@ -67,6 +442,18 @@ func deliverReport(urlReports url.UrlReport) error {
 	} else {
 		fmt.Println(reportOutput)
 	}
+	return storeReportFiles(urlReports)
+}
+
+// Stores UrlReport in all formats in the report directory, if one is given.
+func storeReportFiles(urlReports url.UrlReport) error {
+	if args.ReportDirectory != "" {
+		name, err := url.WriteReportFiles(args.ReportDirectory, urlReports)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(infoOutput, "Stored report %s in %s\n", name, args.ReportDirectory)
+	}
 	return nil
 }
 
@@ -79,33 +466,104 @@ func createUrlReport(httpUrls []url.ExtractedUrl) url.UrlReport {
 		urlReports = url.CreateDryReport(httpUrls)
 	default:
 		urlReports = url.CustomizableCreateUrlReport(httpUrls, args.MaxParallelRequests)
+		if len(args.DescendInto) > 0 {
+			linkedUrls := filterURLs(url.ExtractUrlsFromLinkedDocuments(urlReports, args.DescendInto, &crawlFilterStats, infoOutput))
+			urlReports = urlReports.Merge(url.CustomizableCreateUrlReport(linkedUrls, args.MaxParallelRequests))
+			urlReports.AddMetaData("descended_extracted_urls", fmt.Sprint(len(linkedUrls)))
+		}
 	}
+	return completeUrlReport(urlReports, len(httpUrls))
+}
 
-	urlReports.AddMetaData("total_extracted_urls", fmt.Sprint(len(httpUrls)))
+// Applies ignore rules, adds summary and baseline diff and removes reachable urls if they are not shown.
+func completeUrlReport(urlReports url.UrlReport, extractedUrls int) url.UrlReport {
+	urlReports = ignoreRules.ApplyToReport(urlReports, time.Now())
+	urlReports.AddMetaData("total_extracted_urls", fmt.Sprint(extractedUrls))
+	urlReports.AddSummaryMetaData()
+	urlReports = urlReports.Summarize(args.SlowestUrls)
+	// compare before reachable urls are removed, to find fixed urls
+	if baselineReport != nil {
+		diff := url.DiffReports(*baselineReport, urlReports)
+		diff.AddMetaDataTo(urlReports)
+		baselineDiff = &diff
+	}
 
-	if !args.ShowReachables {
+	// redirected reachable urls are needed for warnings in GitHub Actions
+	if !args.ShowReachables && !args.OutputAsGithub {
 		urlReports = urlReports.CleanupReachableUrls()
 	}
+	if args.SortBy != "" {
+		urlReports = urlReports.SortUrlStatus(args.SortBy)
+	}
 	return urlReports
 }
 
+// Reads all pages and extracts unique urls over all pages. Only fails if no page could be read.
+func extractURLsFromPages(inputUrls []string) ([]url.ExtractedUrl, error) {
+	httpUrls := []url.ExtractedUrl{}
+	var lastErr error
+	readPages := 0
+	for _, inputUrl := range inputUrls {
+		pageUrls, err := extractURLs(inputUrl)
+		if err != nil {
+			fmt.Fprintf(infoOutput, "%v\nWARNING: Failure to extract links from %s\n", err, inputUrl)
+			lastErr = err
+			continue
+		}
+		readPages += 1
+		httpUrls = append(httpUrls, pageUrls...)
+	}
+	if readPages == 0 {
+		return nil, lastErr
+	}
+	return url.MergeExtractedUrls(httpUrls), nil
+}
+
 // Reads url and extracts unique urls with count of occurences
 func extractURLs(inputUrl string) ([]url.ExtractedUrl, error) {
-	fmt.Println("Checking URL: ", inputUrl)
+	fmt.Fprintln(infoOutput, "Checking URL: ", inputUrl)
+
+	body, contentType, err := url.GetDocumentFromUrl(inputUrl)
+	if err != nil {
+		return nil, err
+	}
+	// pages of a kind to descend into use their own link extraction
+	if kind := url.DocumentKindOf(contentType); kind != url.DocumentHtml && slices.Contains(args.DescendInto, kind) {
+		return url.ExtractUrlsFromDocument(kind, body, inputUrl)
+	}
+	return url.ExtractHttpUrlsFromPage(string(body), inputUrl), nil
+}
 
-	body, err := url.GetBodyFromUrl(inputUrl)
+// Reads all documents of a local site and extracts unique urls over all files.
+func extractURLsFromLocalSite(dirOrGlob, baseUrl string) ([]url.ExtractedUrl, error) {
+	files, root, err := url.FindDocumentFiles(dirOrGlob)
+	if err != nil {
+		return nil, err
+	}
+	site, err := url.NewLocalSite(root, baseUrl)
 	if err != nil {
 		return nil, err
 	}
-	httpUrls := url.ExtractHttpUrls(body)
+	fmt.Fprintf(infoOutput, "Checking %d files in %s\n", len(files), root)
 
-	// check for exclusion
-	if args.RegexExclude != "" {
-		httpUrls = url.FilterByExclude(httpUrls, args.RegexExclude)
+	httpUrls := []url.ExtractedUrl{}
+	for _, file := range files {
+		fileUrls, err := site.ExtractUrlsFromFile(file)
+		if err != nil {
+			return nil, err
+		}
+		httpUrls = append(httpUrls, fileUrls...)
 	}
-	// check for inclusion
-	if args.RegexInclude != "" {
-		httpUrls = url.FilterByInclude(httpUrls, args.RegexInclude)
+	return url.MergeExtractedUrls(httpUrls), nil
+}
+
+// Applies include and exclude filters on extracted urls and counts removed urls per rule
+func filterURLs(httpUrls []url.ExtractedUrl) []url.ExtractedUrl {
+	filteredUrls := []url.ExtractedUrl{}
+	for _, e := range httpUrls {
+		if linkFilterStats.Allows(e.Url) {
+			filteredUrls = append(filteredUrls, e)
+		}
 	}
-	return httpUrls, nil
+	return filteredUrls
 }
@@ -1,7 +1,10 @@
 package main_test
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -44,6 +47,16 @@ func TestBasicPageWithoutLinks(t *testing.T) {
 			arguments:     "http://localhost:1337/index.html",
 			wantStatus:    constants.ExitUrlNotReachable,
 			wantOutputEnd: "ERROR: Failure to extract links from given URL.\n",
+		}, {
+			name:          "Version command",
+			arguments:     "version",
+			wantStatus:    constants.ExitSuccess,
+			wantOutputEnd: "2024 - Felix Sponholz\n",
+		}, {
+			name:          "Diff command without reports",
+			arguments:     "diff",
+			wantStatus:    constants.ExitMissingParameter,
+			wantOutputEnd: "ERROR:baseline and current report are required\n",
 		},
 	}
 
@@ -60,6 +73,31 @@ func TestBasicPageWithoutLinks(t *testing.T) {
 	}
 }
 
+func TestJsonOutputOnStdout(t *testing.T) {
+	binName, err := buildBinary()
+	if err != nil {
+		t.Fatalf("failed to build: %v", err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `<html><body><a href="http://%s/missing">missing</a></body></html>`, r.Host)
+	}))
+	defer server.Close()
+
+	cmd := exec.Command(binName, "-json", server.URL+"/index.html")
+	out, _ := cmd.Output()
+	if cmd.ProcessState.ExitCode() != constants.ExitNotAllReportReachable {
+		t.Fatalf("Expected error code %d, got %d", constants.ExitNotAllReportReachable, cmd.ProcessState.ExitCode())
+	}
+	var report map[string]any
+	if err := json.Unmarshal(out, &report); err != nil {
+		t.Fatalf("Expected only the json report on stdout, got %v in %q", err, out)
+	}
+}
+
 // Starts execution of compiled programm with given arguments
 func execBlcheck(binName, arguments string) (string, int, error) {
 	dir, err := os.Getwd()
@@ -2,4 +2,8 @@ module github.com/Felixs/blcheck
 
 go 1.22.3
 
-require mvdan.cc/xurls/v2 v2.5.0
+require (
+	github.com/BurntSushi/toml v1.6.0
+	gopkg.in/yaml.v3 v3.0.1
+	mvdan.cc/xurls/v2 v2.5.0
+)
@@ -1,18 +1,25 @@
 package arguments
 
 import (
+	"bufio"
 	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/Felixs/blcheck/pkg/config"
 	"github.com/Felixs/blcheck/pkg/constants"
 	"github.com/Felixs/blcheck/pkg/url"
 )
 
 const (
 	urlMinLength = 2
+	// Report directory used by -serve if no other is given
+	DefaultReportDirectory = "blcheck-reports"
 )
 
 var (
@@ -20,91 +27,340 @@ var (
 	DisplayVersion bool
 
 	// Report parameter
-	URL            string
-	RegexInclude   string
-	RegexExclude   string
+	URLs           []string
+	InputFile      string
+	LocalDirectory string
+	LocalBaseUrl   string
+	RegexInclude   []string
+	RegexExclude   []string
+	// Include and exclude rules for pages links get extracted from
+	CrawlInclude []string
+	CrawlExclude []string
+	// Matches include and exclude rules as plain substrings instead of regular expressions
+	LiteralFilters bool
+	// Filters built from include and exclude rules
+	LinkFilter     url.UrlFilter
+	CrawlFilter    url.UrlFilter
 	ShowReachables bool
 	ExecuteDryRun  bool
+	DescendInto    []string
+	SortBy         string
+	SlowestUrls    int
+	// Previous json report, exit code only depends on urls that broke since
+	BaselineFile string
+	// File with rules for known broken urls, .blcheckignore is used if it exists
+	IgnoreFile string
 
 	// Constrains for url checks
 	MaxParallelRequests int
 	MaxTimeoutInSeconds int
+	// File with results of previous runs, results younger than their ttl are reused
+	CacheFile      string
+	CacheTTL       time.Duration
+	CacheBrokenTTL time.Duration
+	// Interval of progress lines on stderr if it is not a terminal, 0 disables progress output
+	ProgressInterval time.Duration
 
 	// Output parameter
-	OutputAsJSON bool
-	OutputAsCSV  bool
-	OutputInFile string
+	OutputAsJSON     bool
+	OutputAsCSV      bool
+	OutputAsHTML     bool
+	OutputAsJUnit    bool
+	OutputAsSarif    bool
+	OutputAsMarkdown bool
+	// Streams UrlStatus as NDJSON while checks are running
+	OutputAsNDJSON bool
+	// Prints GitHub Actions workflow commands and writes a job summary
+	OutputAsGithub bool
+	OutputInFile   string
+	// Adds MetaData as comment lines to csv output
+	OutputCsvMetaData bool
+	ReportDirectory   string
+
+	// Crawl parameter, 0 crawls without limit
+	MaxCrawlDepth int
+	MaxCrawlPages int
+	// File the crawl state is saved to, and state file of a stopped crawl to continue
+	CrawlStateFile  string
+	CrawlResumeFile string
+	// State read from CrawlResumeFile, nil if a new crawl is started
+	CrawlResumeState *url.CrawlState
+
+	// Server parameter
+	ServeAddress string
+
+	// Diff parameter, reports to compare
+	DiffBaselineFile string
+	DiffCurrentFile  string
+
+	// Config file with options, request rules and ignore list, blcheck.yaml, .toml or .json is used if it exists
+	ConfigFile string
+	// Ignore rules of the config file
+	ConfigIgnoreRules url.IgnoreRules
+
+	// Subcommand given as first argument
+	Command string
+	// Flags of the parsed command
+	commandFlags = flag.NewFlagSet("blcheck", flag.ExitOnError)
+	// Names of the options of all commands
+	knownOptions = map[string]bool{}
+	// Environment variable or config file of options not given on the command line, by long name
+	optionSources = map[string]string{}
 
 	// Error message on flag errors/missmatch
 	ErrorMessage string
 )
 
-// Parses the command line arguments and checks if they all needed arguments are present.
+// Long names of flags that have a short alias, options are named by the long name
+var longFlagNames = map[string]string{
+	"mpr": "max-parallel-requests", "mrt": "max-response-timeout",
+	"j": "json", "c": "csv", "ht": "html", "ju": "junit", "sa": "sarif", "nd": "ndjson", "md": "markdown", "gh": "github",
+	"in": "include", "ex": "exclude", "d": "dry", "o": "out", "rd": "report-dir", "sr": "show-reachable",
+	"i": "input", "dir": "directory", "bu": "base-url", "bl": "baseline", "dc": "descend",
+}
+
+// Prefix of environment variables that set options
+const EnvPrefix = "BLCHECK_"
+
+// Environment variable with urls to check if none are given as arguments, separated by whitespace
+const EnvUrls = EnvPrefix + "URLS"
+
+// Flags that can be given several times, list values in config files set them once per entry
+var repeatableFlags = []string{"include", "exclude", "crawl-include", "crawl-exclude"}
+
+// Parses the command line arguments of the command given as first argument, check is used if none is given.
+// Checks if all needed arguments are present.
 func Parse() {
-	// TODO: if using 2 different flag names for a single values flag.Usage needs to be overwritten
-	// Version
-	flag.BoolVar(&DisplayVersion, "version", false, "Displays version of blcheck")
-	flag.BoolVar(&DisplayVersion, "v", false, "Displays version of blcheck")
-	// Ratelimit / parallel requests
-	flag.IntVar(&MaxParallelRequests, "max-parallel-requests", url.MaxNumParallelQueries, "Maximum number of parallel requests executed")
-	flag.IntVar(&MaxParallelRequests, "mpr", url.MaxNumParallelQueries, "Maximum number of parallel requests executed")
-	// Timeout
-	flag.IntVar(&MaxTimeoutInSeconds, "max-response-timeout", int(url.DefaultHttpGetTimeout.Seconds()), "Maximum timeout wait on requests in seconds")
-	flag.IntVar(&MaxTimeoutInSeconds, "mrt", int(url.DefaultHttpGetTimeout.Seconds()), "Maximum timeout wait on requests in seconds")
-	// Output as json flag
-	flag.BoolVar(&OutputAsJSON, "json", false, "Export output as json format")
-	flag.BoolVar(&OutputAsJSON, "j", false, "Export output as json format")
-	// Output as csv flag
-	flag.BoolVar(&OutputAsCSV, "csv", false, "Export output as csv format")
-	flag.BoolVar(&OutputAsCSV, "c", false, "Export output as csv format")
-	// Include flag for which string needs to be present in url to check
-	flag.StringVar(&RegexInclude, "include", "", "Parsed urls need to contain this string to get checked")
-	flag.StringVar(&RegexInclude, "in", "", "Parsed urls need to contain this string to get checked")
-	// Exclude flag for which string can not be present in url to check
-	flag.StringVar(&RegexExclude, "exclude", "", "Parsed urls need to not contain this string to get checked")
-	flag.StringVar(&RegexExclude, "ex", "", "Parsed urls need to not contain this string to get checked")
-	// Flag if tool should run in dry mode, only getting links from initial webpage
-	flag.BoolVar(&ExecuteDryRun, "dry", false, "Only gets urls from initial webpage and does not check the status of other urls")
-	flag.BoolVar(&ExecuteDryRun, "d", false, "Only gets urls from initial webpage and does not check the status of other urls")
-	// Flag if output should be writen into file, gives path an name of file
-	flag.StringVar(&OutputInFile, "o", "", "Writes output to given location. If directory is given, writes to blcheck.log in directory.")
-	flag.StringVar(&OutputInFile, "out", "", "Writes output to given location. If directory is given, writes to blcheck.log in directory.")
-	// Flag if reachable urls should be included into the output
-	flag.BoolVar(&ShowReachables, "sr", false, "Includes reachable urls in report")
-	flag.BoolVar(&ShowReachables, "show-reachable", false, "Includes reachable urls in report")
-
-	// setting own print function, to handle positonal arguments
-	flag.Usage = printUsage
-	flag.Parse()
-
-	if DisplayVersion {
+	arguments := slices.Clone(os.Args[1:])
+	Command = CommandCheck
+	if len(arguments) > 0 {
+		switch arguments[0] {
+		case "-v", "-version", "--version":
+			arguments[0] = CommandVersion
+		case "help":
+			// help of a command is shown with its -help flag
+			arguments = append(arguments[1:min(len(arguments), 2)], "-help")
+		}
+		if _, found := findCommand(arguments[0]); found {
+			Command = arguments[0]
+			arguments = arguments[1:]
+		}
+	}
+	// options of all commands are known before flags are bound to their defaults
+	knownOptions = allOptionNames()
+	c, _ := findCommand(Command)
+	commandFlags = newCommandFlagSet(c)
+	commandFlags.Usage = printUsage
+	commandFlags.Parse(arguments)
+
+	setFlags := map[string]bool{}
+	commandFlags.Visit(func(f *flag.Flag) { setFlags[longFlagName(f.Name)] = true })
+	if err := applyEnvironment(commandFlags, setFlags, os.LookupEnv); err != nil {
+		writeUsageAndExit(err.Error(), constants.ExitErrorInParameterEvaluation)
+	}
+
+	switch Command {
+	case CommandVersion:
 		fmt.Println("blcheck " + Version + "\n2024 - Felix Sponholz")
 		os.Exit(constants.ExitSuccess)
+	case CommandDiff:
+		if commandFlags.NArg() != 2 {
+			writeUsageAndExit("baseline and current report are required", constants.ExitMissingParameter)
+		}
+		DiffBaselineFile = commandFlags.Arg(0)
+		DiffCurrentFile = commandFlags.Arg(1)
+		return
+	case CommandValidateConfig:
+		validateConfigAndExit()
 	}
-	if flag.NArg() != 1 {
+
+	configUrls, err := applyConfigFile(commandFlags, setFlags)
+	if err != nil {
+		writeUsageAndExit(err.Error(), constants.ExitErrorInParameterEvaluation)
+	}
+
+	URLs = commandFlags.Args()
+	if CrawlResumeFile != "" {
+		if len(URLs) > 0 || InputFile != "" {
+			writeUsageAndExit("urls of a resumed crawl are read from the state file", constants.ExitErrorInParameterEvaluation)
+		}
+		state, err := url.ReadCrawlState(CrawlResumeFile)
+		if err != nil {
+			writeUsageAndExit(err.Error(), constants.ExitFailedToReadInput)
+		}
+		if err := state.CheckLimits(MaxCrawlDepth, MaxCrawlPages); err != nil {
+			writeUsageAndExit(err.Error(), constants.ExitErrorInParameterEvaluation)
+		}
+		CrawlResumeState = &state
+		URLs = state.StartUrls
+	}
+	if Command == CommandFiles {
+		if len(URLs) != 1 {
+			writeUsageAndExit("one directory or glob is required", constants.ExitMissingParameter)
+		}
+		LocalDirectory = URLs[0]
+		URLs = []string{}
+	}
+	if len(URLs) == 0 && Command != CommandFiles {
+		URLs = strings.Fields(os.Getenv(EnvUrls))
+	}
+	if len(URLs) == 0 && Command != CommandFiles {
+		URLs = configUrls
+	}
+	if InputFile != "" {
+		inputUrls, err := readUrlFile(InputFile)
+		if err != nil {
+			writeUsageAndExit(err.Error(), constants.ExitFailedToReadInput)
+		}
+		URLs = append(URLs, inputUrls...)
+	}
+	if len(URLs) == 0 && LocalDirectory == "" && Command != CommandServe {
 		writeUsageAndExit("URL is required", constants.ExitMissingParameter)
 	}
-	URL = flag.Arg(0)
 
-	if err := checkOutputFormats([]bool{OutputAsJSON, OutputAsCSV}); err != nil {
+	if err := checkOutputFormats([]bool{OutputAsJSON, OutputAsCSV, OutputAsHTML, OutputAsJUnit, OutputAsSarif, OutputAsMarkdown, OutputAsGithub, OutputAsNDJSON}); err != nil {
 		writeUsageAndExit(err.Error(), constants.ExitToManyOutputFormats)
 	}
 
-	if err := checkUrlParameter(URL); err != nil {
+	for _, u := range URLs {
+		if err := checkUrlParameter(u); err != nil {
+			writeUsageAndExit(err.Error(), constants.ExitErrorInParameterEvaluation)
+		}
+	}
+
+	if err := checkOptions(); err != nil {
 		writeUsageAndExit(err.Error(), constants.ExitErrorInParameterEvaluation)
 	}
 
 	if err := checkMaxParallelRequests(MaxParallelRequests); err != nil {
-		writeUsageAndExit(err.Error(), constants.ExitInvalidNumberMaxParallelRequests)
+		writeUsageAndExit(optionError("max-parallel-requests", err).Error(), constants.ExitInvalidNumberMaxParallelRequests)
 	}
 
 	if err := checkMaxTimeoutInSeconds(MaxTimeoutInSeconds); err != nil {
-		writeUsageAndExit(err.Error(), constants.ExitInlvaidNumberMaxTimeoutInSeconds)
+		writeUsageAndExit(optionError("max-response-timeout", err).Error(), constants.ExitInlvaidNumberMaxTimeoutInSeconds)
 	}
 
 	checkArgument()
 }
 
+// Checks options that are not validated by parsing their flag.
+func checkOptions() error {
+	if err := buildFilters(); err != nil {
+		return err
+	}
+	if err := checkSortKey(SortBy); err != nil {
+		return optionError("sort", err)
+	}
+	if SlowestUrls < 0 {
+		return optionError("slowest", errors.New("number of slowest urls can not be negative"))
+	}
+	if MaxCrawlDepth < 0 {
+		return optionError("max-depth", errors.New("max depth of a crawl can not be negative"))
+	}
+	if MaxCrawlPages < 0 {
+		return optionError("max-pages", errors.New("max pages of a crawl can not be negative"))
+	}
+	if CacheTTL < 0 {
+		return optionError("cache-ttl", errors.New("cache ttl can not be negative"))
+	}
+	if CacheBrokenTTL < 0 {
+		return optionError("cache-broken-ttl", errors.New("cache ttl of broken urls can not be negative"))
+	}
+	if ProgressInterval < 0 {
+		return optionError("progress-interval", errors.New("progress interval can not be negative"))
+	}
+	return nil
+}
+
+// Prefixes err with the environment variable or config file that set option name, options given
+// on the command line are named by the error itself.
+func optionError(name string, err error) error {
+	if source, ok := optionSources[name]; ok {
+		return fmt.Errorf("%s: %w", source, err)
+	}
+	return err
+}
+
+// Validates the config file against the options of all commands, prints the result and exits.
+func validateConfigAndExit() {
+	path := ConfigFile
+	if path == "" {
+		var found bool
+		if path, found = config.Discover("."); !found {
+			writeUsageAndExit("no config file found, expected one of "+strings.Join(config.DefaultFileNames, ", "), constants.ExitMissingParameter)
+		}
+	}
+	c, err := validateConfig(path)
+	if err != nil {
+		fmt.Println("ERROR:" + err.Error())
+		os.Exit(constants.ExitErrorInParameterEvaluation)
+	}
+	ignoreRules, _ := c.IgnoreRules()
+	ignoreRules.WarnExpired(os.Stderr, "ignore list of "+path, time.Now())
+	rules, _ := c.RequestRules()
+	fmt.Printf("%s is valid: %d options, %d request rules, %d ignore rules, %d urls\n",
+		path, len(c.Options), len(rules), len(c.Ignore), len(c.Urls))
+	os.Exit(constants.ExitSuccess)
+}
+
+// Reads config file and checks its options with the flags of every command that knows them.
+// Options are bound to the package variables, so no command can run after validation.
+func validateConfig(path string) (config.Config, error) {
+	c, err := config.Load(path)
+	if err != nil {
+		return config.Config{}, err
+	}
+	if _, err := c.RequestRules(); err != nil {
+		return config.Config{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if _, err := c.IgnoreRules(); err != nil {
+		return config.Config{}, fmt.Errorf("%s: %w", path, err)
+	}
+	// errors are named by path, environment variables are not applied to the flags of the commands
+	optionSources = map[string]string{}
+	for _, cmd := range commands {
+		if cmd.name == CommandValidateConfig {
+			continue
+		}
+		if err := applyOptions(newCommandFlagSet(cmd), c.Options, map[string]bool{}); err != nil {
+			return config.Config{}, fmt.Errorf("%s: %w", path, err)
+		}
+		if err := checkOptions(); err != nil {
+			return config.Config{}, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return c, nil
+}
+
+// Returns all options that influence the result of a check, as sorted key=value list.
+func EffectiveConfiguration() string {
+	options := []string{
+		fmt.Sprintf("max_parallel_requests=%d", MaxParallelRequests),
+		fmt.Sprintf("max_response_timeout=%ds", MaxTimeoutInSeconds),
+		fmt.Sprintf("include=%s", strings.Join(RegexInclude, ",")),
+		fmt.Sprintf("exclude=%s", strings.Join(RegexExclude, ",")),
+		fmt.Sprintf("crawl_include=%s", strings.Join(CrawlInclude, ",")),
+		fmt.Sprintf("crawl_exclude=%s", strings.Join(CrawlExclude, ",")),
+		fmt.Sprintf("literal=%v", LiteralFilters),
+		fmt.Sprintf("show_reachable=%v", ShowReachables),
+		fmt.Sprintf("dry_run=%v", ExecuteDryRun),
+		fmt.Sprintf("descend=%s", strings.Join(DescendInto, ",")),
+		fmt.Sprintf("base_url=%s", LocalBaseUrl),
+		fmt.Sprintf("sort=%s", SortBy),
+		fmt.Sprintf("ignore_file=%s", IgnoreFile),
+		fmt.Sprintf("config=%s", ConfigFile),
+		fmt.Sprintf("command=%s", Command),
+		fmt.Sprintf("max_depth=%d", MaxCrawlDepth),
+		fmt.Sprintf("max_pages=%d", MaxCrawlPages),
+		fmt.Sprintf("resume=%s", CrawlResumeFile),
+		fmt.Sprintf("cache=%s", CacheFile),
+		fmt.Sprintf("cache_ttl=%s", CacheTTL),
+		fmt.Sprintf("cache_broken_ttl=%s", CacheBrokenTTL),
+	}
+	slices.Sort(options)
+	return strings.Join(options, " ")
+}
+
 // Write usage text with explicit error message and exits with code.
 func writeUsageAndExit(errorMessage string, statusCode int) {
 	ErrorMessage = errorMessage
@@ -121,6 +377,53 @@ func checkUrlParameter(urlInput string) error {
 	return nil
 }
 
+// Parses comma separated list of document kinds to descend into.
+func parseDescendInto(value string) error {
+	kinds := []string{}
+	for _, kind := range strings.Split(value, ",") {
+		kind = strings.ToLower(strings.TrimSpace(kind))
+		if kind == "" {
+			continue
+		}
+		if !slices.Contains(url.DocumentKinds, kind) {
+			return fmt.Errorf("unknown document kind %q, use one of %s", kind, strings.Join(url.DocumentKinds, ","))
+		}
+		kinds = append(kinds, kind)
+	}
+	DescendInto = kinds
+	return nil
+}
+
+// Reads urls from file at path or from stdin if path is "-".
+func readUrlFile(path string) ([]string, error) {
+	if path == "-" {
+		return readUrlList(os.Stdin)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read url input file: %w", err)
+	}
+	defer file.Close()
+	return readUrlList(file)
+}
+
+// Reads one url per line, ignores empty lines and lines starting with #.
+func readUrlList(r io.Reader) ([]string, error) {
+	urls := []string{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		urls = append(urls, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read url list: %w", err)
+	}
+	return urls, nil
+}
+
 // Checks if only max one output format is chosen.
 func checkOutputFormats(outputFormats []bool) error {
 	findCounter := 0
@@ -135,6 +438,146 @@ func checkOutputFormats(outputFormats []bool) error {
 	return nil
 }
 
+// Reads -config or the first default config file that exists, applies its options that are not set
+// on the command line and its request rules. Returns the urls of the config file.
+func applyConfigFile(flags *flag.FlagSet, setFlags map[string]bool) ([]string, error) {
+	if ConfigFile == "" {
+		path, found := config.Discover(".")
+		if !found {
+			return nil, nil
+		}
+		ConfigFile = path
+	}
+	c, err := config.Load(ConfigFile)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyOptions(flags, c.Options, setFlags); err != nil {
+		return nil, fmt.Errorf("%s: %w", ConfigFile, err)
+	}
+	for name := range c.Options {
+		if !setFlags[longFlagName(name)] && flags.Lookup(name) != nil {
+			optionSources[longFlagName(name)] = "config file " + ConfigFile
+		}
+	}
+	rules, err := c.RequestRules()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ConfigFile, err)
+	}
+	url.SetRequestRules(rules)
+	if ConfigIgnoreRules, err = c.IgnoreRules(); err != nil {
+		return nil, fmt.Errorf("%s: %w", ConfigFile, err)
+	}
+	return c.Urls, nil
+}
+
+// Sets options on flags, options whose flag is in setFlags or that belong to other commands are skipped.
+func applyOptions(flags *flag.FlagSet, options map[string][]string, setFlags map[string]bool) error {
+	names := []string{}
+	for name := range options {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		known := flags.Lookup(name) != nil || knownOptions[longFlagName(name)]
+		if name == "config" || !known {
+			return fmt.Errorf("unknown option %q", name)
+		}
+		if setFlags[longFlagName(name)] || flags.Lookup(name) == nil {
+			continue
+		}
+		values := options[name]
+		if !slices.Contains(repeatableFlags, longFlagName(name)) {
+			values = []string{strings.Join(values, ",")}
+		}
+		for _, value := range values {
+			if err := flags.Set(name, value); err != nil {
+				return fmt.Errorf("option %q: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Name of the environment variable that sets the option of flag name, e.g. BLCHECK_MAX_PARALLEL_REQUESTS.
+func environmentVariable(name string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(longFlagName(name), "-", "_"))
+}
+
+// Sets flags from environment variables, flags in setFlags are skipped and applied flags are added to setFlags
+// and optionSources.
+// Values of repeatable flags are separated by newlines.
+func applyEnvironment(flags *flag.FlagSet, setFlags map[string]bool, lookupEnv func(string) (string, bool)) error {
+	var err error
+	flags.VisitAll(func(f *flag.Flag) {
+		name := longFlagName(f.Name)
+		if err != nil || f.Name != name || setFlags[name] || name == "version" {
+			return
+		}
+		value, ok := lookupEnv(environmentVariable(name))
+		if !ok {
+			return
+		}
+		values := []string{value}
+		if slices.Contains(repeatableFlags, name) {
+			values = strings.FieldsFunc(value, func(r rune) bool { return r == '\n' || r == '\r' })
+		}
+		for _, v := range values {
+			if setErr := flags.Set(name, v); setErr != nil {
+				err = fmt.Errorf("environment variable %s: invalid value %q: %w", environmentVariable(name), v, setErr)
+				return
+			}
+		}
+		setFlags[name] = true
+		optionSources[name] = "environment variable " + environmentVariable(name)
+	})
+	return err
+}
+
+// Adds the name of the environment variable to the usage text of each flag.
+func addEnvironmentToUsage(flags *flag.FlagSet) {
+	flags.VisitAll(func(f *flag.Flag) {
+		if longFlagName(f.Name) != "version" {
+			f.Usage += " (env " + environmentVariable(f.Name) + ")"
+		}
+	})
+}
+
+// Long name of a flag given by its short alias or long name.
+func longFlagName(name string) string {
+	if long, ok := longFlagNames[name]; ok {
+		return long
+	}
+	return name
+}
+
+// Creates flag function that appends every given value to target.
+func appendTo(target *[]string) func(string) error {
+	return func(value string) error {
+		*target = append(*target, value)
+		return nil
+	}
+}
+
+// Builds LinkFilter and CrawlFilter from include and exclude rules.
+func buildFilters() error {
+	var err error
+	LinkFilter, err = url.NewUrlFilter(RegexInclude, RegexExclude, LiteralFilters)
+	if err != nil {
+		return err
+	}
+	CrawlFilter, err = url.NewUrlFilter(CrawlInclude, CrawlExclude, LiteralFilters)
+	return err
+}
+
+// Checks if urls can be sorted by key, empty key keeps the order urls were found in.
+func checkSortKey(key string) error {
+	if key != "" && !slices.Contains(url.SortKeys, key) {
+		return fmt.Errorf("unknown sort order %q, use one of %s", key, strings.Join(url.SortKeys, ","))
+	}
+	return nil
+}
+
 // Checks if MaxParallelRequests is positiv integer.
 func checkMaxParallelRequests(maxParallelRequests int) error {
 	if maxParallelRequests <= 0 {
@@ -155,11 +598,20 @@ func checkMaxTimeoutInSeconds(maxTimeoutInSeconds int) error {
 
 // Validate content of arguments.
 func checkArgument() {
-	// check URL for protocol prefix
-	url.InferHttpsPrefix(&URL)
-	// basic check if given string might be an url
-	if !url.IsUrlValid(URL) {
-		ErrorMessage = fmt.Sprintf("Not a valid url %s", URL)
+	for i := range URLs {
+		// check URL for protocol prefix
+		if url.InferHttpsPrefix(&URLs[i]) {
+			fmt.Fprintf(os.Stderr, "Infered https:// prefix for %s, because given url did not have a protocol\n", URLs[i])
+		}
+		// basic check if given string might be an url
+		if !url.IsUrlValid(URLs[i]) {
+			ErrorMessage = fmt.Sprintf("Not a valid url %s", URLs[i])
+			printUsage()
+			os.Exit(constants.ExitInvalidUrlParameter)
+		}
+	}
+	if LocalDirectory != "" && !url.IsUrlValid(LocalBaseUrl) {
+		ErrorMessage = fmt.Sprintf("Not a valid base url %s", LocalBaseUrl)
 		printUsage()
 		os.Exit(constants.ExitInvalidUrlParameter)
 	}
@@ -167,16 +619,38 @@ func checkArgument() {
 	url.SetHttpGetTimeoutSeconds(time.Duration(MaxTimeoutInSeconds) * time.Second)
 }
 
-// Prints how to use the tool to stdout, with an error message if present.
+// Prints how to use the parsed command to stdout, with an error message if present.
+// The usage of the check command lists all commands.
 func printUsage() {
-
+	c, found := findCommand(Command)
+	if !found {
+		c, _ = findCommand(CommandCheck)
+	}
 	fmt.Printf(`blcheck (%s)- A simple tool to check which links on your websites are broken.
-	
-Usage: blcheck <URL>
-`, Version)
-	flag.CommandLine.SetOutput(os.Stdout)
-	flag.PrintDefaults()
+
+Usage: %s
+
+%s
+`, Version, c.usage, c.description)
+	if c.name == CommandCheck {
+		fmt.Println("\nCommands:")
+		for _, other := range commands {
+			fmt.Printf("  %-16s%s\n", other.name, strings.SplitN(other.description, "\n", 2)[0])
+		}
+		fmt.Println("\nRun blcheck <command> -help for the flags of a command.")
+	}
+	if hasFlags(commandFlags) {
+		fmt.Println("\nFlags:")
+		printFlags(os.Stdout, commandFlags)
+	}
 	if ErrorMessage != "" {
 		fmt.Println("ERROR:" + ErrorMessage)
 	}
 }
+
+// Checks if flag set has any flags defined.
+func hasFlags(fs *flag.FlagSet) bool {
+	found := false
+	fs.VisitAll(func(*flag.Flag) { found = true })
+	return found
+}
@@ -1,25 +1,61 @@
 package arguments
 
 import (
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
+// Flag set with options used by the tests of config files and environment variables.
+type testFlags struct {
+	flags    *flag.FlagSet
+	parallel *int
+	includes *[]string
+	sort     *string
+	slowest  *int
+}
+
+// Creates test flags, mpr is a short alias of max-parallel-requests.
+func newTestFlags() testFlags {
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	f := testFlags{flags: flags, includes: &[]string{}}
+	f.parallel = flags.Int("max-parallel-requests", 5, "")
+	flags.Int("mpr", 5, "")
+	flags.Func("include", "", appendTo(f.includes))
+	f.sort = flags.String("sort", "", "")
+	f.slowest = flags.Int("slowest", 0, "")
+	return f
+}
+
 func TestCheckArguments(t *testing.T) {
 	t.Run("url string must contain at least 2 characters", func(t *testing.T) {
-		URL = "a"
-		err := checkUrlParameter(URL)
+		err := checkUrlParameter("a")
 		if err == nil {
 			t.Errorf("Expected an failure")
 		}
 	})
 	t.Run("url with port an path needs to pass", func(t *testing.T) {
-		URL = "http://localhost:1337/index.html"
-		err := checkUrlParameter(URL)
+		err := checkUrlParameter("http://localhost:1337/index.html")
 		if err != nil {
 			t.Errorf("Unexpected error, %v", err)
 		}
 	})
 
+	t.Run("sort order must be known", func(t *testing.T) {
+		if err := checkSortKey("host"); err != nil {
+			t.Errorf("Unexpected error, %v", err)
+		}
+		if err := checkSortKey(""); err != nil {
+			t.Errorf("Unexpected error, %v", err)
+		}
+		if err := checkSortKey("size"); err == nil {
+			t.Errorf("Expected an failure")
+		}
+	})
+
 	t.Run("max one output format can be defined, trying one", func(t *testing.T) {
 		formats := []bool{true}
 		err := checkOutputFormats(formats)
@@ -85,3 +121,229 @@ func TestCheckArguments(t *testing.T) {
 	})
 
 }
+
+func TestReadUrlList(t *testing.T) {
+	t.Run("one url per line, skipping empty lines and comments", func(t *testing.T) {
+		input := "https://www.google.de\n\n# comment\n  http://localhost:1337/index.html  \n"
+		got, err := readUrlList(strings.NewReader(input))
+		want := []string{"https://www.google.de", "http://localhost:1337/index.html"}
+		if err != nil {
+			t.Fatalf("Unexpected error, %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v want %v", got, want)
+		}
+	})
+
+	t.Run("empty input gives empty list", func(t *testing.T) {
+		got, err := readUrlList(strings.NewReader(""))
+		if err != nil {
+			t.Fatalf("Unexpected error, %v", err)
+		}
+		if len(got) != 0 {
+			t.Errorf("Expected empty list, got %v", got)
+		}
+	})
+}
+
+func TestParseDescendInto(t *testing.T) {
+	descendInto := DescendInto
+	t.Cleanup(func() { DescendInto = descendInto })
+	t.Run("known kinds are accepted", func(t *testing.T) {
+		err := parseDescendInto("pdf, JSON,,feed")
+		want := []string{"pdf", "json", "feed"}
+		if err != nil {
+			t.Fatalf("Unexpected error, %v", err)
+		}
+		if !reflect.DeepEqual(DescendInto, want) {
+			t.Errorf("got %v want %v", DescendInto, want)
+		}
+	})
+
+	t.Run("unknown kind fails", func(t *testing.T) {
+		err := parseDescendInto("pdf,docx")
+		if err == nil {
+			t.Errorf("Expected error")
+		}
+	})
+}
+
+func TestBuildFilters(t *testing.T) {
+	regexInclude, regexExclude, crawlInclude, crawlExclude := RegexInclude, RegexExclude, CrawlInclude, CrawlExclude
+	literalFilters, linkFilter, crawlFilter := LiteralFilters, LinkFilter, CrawlFilter
+	t.Cleanup(func() {
+		RegexInclude, RegexExclude, CrawlInclude, CrawlExclude = regexInclude, regexExclude, crawlInclude, crawlExclude
+		LiteralFilters, LinkFilter, CrawlFilter = literalFilters, linkFilter, crawlFilter
+	})
+	t.Run("repeated rules", func(t *testing.T) {
+		RegexInclude = []string{`^https://a\.de`, `^https://b\.de`}
+		RegexExclude = []string{`\.pdf$`}
+		CrawlExclude = []string{"/archive/"}
+		if err := buildFilters(); err != nil {
+			t.Fatalf("did not expect an error, got %v", err)
+		}
+		if len(LinkFilter.Rules) != 3 || len(CrawlFilter.Rules) != 1 {
+			t.Errorf("got %d link rules and %d crawl rules expected 3 and 1", len(LinkFilter.Rules), len(CrawlFilter.Rules))
+		}
+	})
+	t.Run("invalid regular expression", func(t *testing.T) {
+		RegexExclude = []string{"("}
+		if err := buildFilters(); err == nil {
+			t.Error("expected an error")
+		}
+	})
+	t.Run("invalid regular expression as literal", func(t *testing.T) {
+		RegexExclude = []string{"("}
+		LiteralFilters = true
+		if err := buildFilters(); err != nil {
+			t.Errorf("did not expect an error, got %v", err)
+		}
+	})
+}
+
+func TestApplyOptions(t *testing.T) {
+	t.Run("options not set on command line", func(t *testing.T) {
+		f := newTestFlags()
+		err := applyOptions(f.flags, map[string][]string{
+			"max-parallel-requests": {"10"},
+			"include":               {"a", "b"},
+			"sort":                  {"host"},
+		}, map[string]bool{"sort": true})
+		if err != nil {
+			t.Fatalf("did not expect an error, got %v", err)
+		}
+		if *f.parallel != 10 || len(*f.includes) != 2 || *f.sort != "" {
+			t.Errorf("got parallel %d, includes %v and sort %q", *f.parallel, *f.includes, *f.sort)
+		}
+	})
+	t.Run("short alias set on command line", func(t *testing.T) {
+		f := newTestFlags()
+		setFlags := map[string]bool{longFlagName("mpr"): true}
+		if err := applyOptions(f.flags, map[string][]string{"max-parallel-requests": {"10"}}, setFlags); err != nil {
+			t.Fatalf("did not expect an error, got %v", err)
+		}
+		if *f.parallel != 5 {
+			t.Errorf("got parallel %d expected command line value to take precedence", *f.parallel)
+		}
+	})
+	t.Run("unknown option", func(t *testing.T) {
+		if err := applyOptions(newTestFlags().flags, map[string][]string{"sorty": {"url"}}, nil); err == nil {
+			t.Error("expected an error")
+		}
+	})
+	t.Run("invalid value", func(t *testing.T) {
+		if err := applyOptions(newTestFlags().flags, map[string][]string{"max-parallel-requests": {"many"}}, nil); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
+
+func TestApplyEnvironment(t *testing.T) {
+	environment := func(variables map[string]string) func(string) (string, bool) {
+		return func(name string) (string, bool) {
+			value, ok := variables[name]
+			return value, ok
+		}
+	}
+	t.Run("variables of options not set on command line", func(t *testing.T) {
+		f := newTestFlags()
+		setFlags := map[string]bool{}
+		err := applyEnvironment(f.flags, setFlags, environment(map[string]string{
+			"BLCHECK_MAX_PARALLEL_REQUESTS": "10",
+			"BLCHECK_INCLUDE":               "a{1,2}\nb\n",
+		}))
+		if err != nil {
+			t.Fatalf("did not expect an error, got %v", err)
+		}
+		if *f.parallel != 10 || !reflect.DeepEqual(*f.includes, []string{"a{1,2}", "b"}) {
+			t.Errorf("got parallel %d and includes %v", *f.parallel, *f.includes)
+		}
+		if !setFlags["max-parallel-requests"] || !setFlags["include"] {
+			t.Errorf("expected applied options to be marked as set, got %v", setFlags)
+		}
+	})
+	t.Run("command line takes precedence", func(t *testing.T) {
+		f := newTestFlags()
+		setFlags := map[string]bool{"max-parallel-requests": true}
+		if err := applyEnvironment(f.flags, setFlags, environment(map[string]string{"BLCHECK_MAX_PARALLEL_REQUESTS": "10"})); err != nil {
+			t.Fatalf("did not expect an error, got %v", err)
+		}
+		if *f.parallel != 5 {
+			t.Errorf("got parallel %d expected default", *f.parallel)
+		}
+	})
+	t.Run("error names variable", func(t *testing.T) {
+		err := applyEnvironment(newTestFlags().flags, map[string]bool{}, environment(map[string]string{"BLCHECK_MAX_PARALLEL_REQUESTS": "many"}))
+		if err == nil || !strings.Contains(err.Error(), "BLCHECK_MAX_PARALLEL_REQUESTS") {
+			t.Errorf("expected error naming the variable, got %v", err)
+		}
+	})
+}
+
+func TestEnvironmentVariable(t *testing.T) {
+	cases := map[string]string{
+		"max-parallel-requests": "BLCHECK_MAX_PARALLEL_REQUESTS",
+		"mpr":                   "BLCHECK_MAX_PARALLEL_REQUESTS",
+		"json":                  "BLCHECK_JSON",
+		"dir":                   "BLCHECK_DIRECTORY",
+	}
+	for name, want := range cases {
+		if got := environmentVariable(name); got != want {
+			t.Errorf("got %q for %q expected %q", got, name, want)
+		}
+	}
+}
+
+func TestOptionError(t *testing.T) {
+	sources, configFile, configIgnoreRules := optionSources, ConfigFile, ConfigIgnoreRules
+	t.Cleanup(func() { optionSources, ConfigFile, ConfigIgnoreRules = sources, configFile, configIgnoreRules })
+	t.Run("environment variable", func(t *testing.T) {
+		optionSources = map[string]string{}
+		f := newTestFlags()
+		lookupEnv := func(name string) (string, bool) { return "bogus", name == "BLCHECK_SORT" }
+		if err := applyEnvironment(f.flags, map[string]bool{}, lookupEnv); err != nil {
+			t.Fatalf("did not expect an error, got %v", err)
+		}
+		err := optionError("sort", checkSortKey(*f.sort))
+		if err == nil || !strings.HasPrefix(err.Error(), "environment variable BLCHECK_SORT: unknown sort order") {
+			t.Errorf("expected error naming the variable, got %v", err)
+		}
+	})
+	t.Run("config file", func(t *testing.T) {
+		optionSources = map[string]string{}
+		ConfigFile = filepath.Join(t.TempDir(), "blcheck.yaml")
+		if err := os.WriteFile(ConfigFile, []byte("slowest: -1\nsort: url\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := applyConfigFile(newTestFlags().flags, map[string]bool{"sort": true}); err != nil {
+			t.Fatalf("did not expect an error, got %v", err)
+		}
+		want := map[string]string{"slowest": "config file " + ConfigFile}
+		if !reflect.DeepEqual(optionSources, want) {
+			t.Errorf("got sources %v expected %v", optionSources, want)
+		}
+	})
+	t.Run("command line", func(t *testing.T) {
+		optionSources = map[string]string{}
+		err := optionError("sort", checkSortKey("bogus"))
+		if err == nil || !strings.HasPrefix(err.Error(), "unknown sort order") {
+			t.Errorf("expected error without source, got %v", err)
+		}
+	})
+}
+
+func TestEffectiveConfiguration(t *testing.T) {
+	maxParallelRequests, maxTimeoutInSeconds, descendInto := MaxParallelRequests, MaxTimeoutInSeconds, DescendInto
+	t.Cleanup(func() {
+		MaxParallelRequests, MaxTimeoutInSeconds, DescendInto = maxParallelRequests, maxTimeoutInSeconds, descendInto
+	})
+	MaxParallelRequests = 3
+	MaxTimeoutInSeconds = 7
+	DescendInto = []string{"pdf", "json"}
+	got := EffectiveConfiguration()
+	for _, want := range []string{"max_parallel_requests=3", "max_response_timeout=7s", "descend=pdf,json"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q to contain %q", got, want)
+		}
+	}
+}
@@ -0,0 +1,300 @@
+package arguments
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Felixs/blcheck/pkg/config"
+	"github.com/Felixs/blcheck/pkg/url"
+)
+
+// Subcommands of blcheck
+const (
+	CommandCheck          = "check"
+	CommandCrawl          = "crawl"
+	CommandFiles          = "files"
+	CommandDiff           = "diff"
+	CommandServe          = "serve"
+	CommandValidateConfig = "validate-config"
+	CommandVersion        = "version"
+)
+
+// Address the serve command listens on if no other is given
+const DefaultServeAddress = "localhost:8080"
+
+// Interval of progress lines if stderr is not a terminal
+const DefaultProgressInterval = 10 * time.Second
+
+// Subcommand with its usage text and flags.
+type command struct {
+	name        string
+	usage       string
+	description string
+	addFlags    func(*flag.FlagSet)
+}
+
+// All subcommands, check is used if no command is given
+var commands = []command{
+	{
+		name:        CommandCheck,
+		usage:       "blcheck [check] [flags] <URL> [URL...]\n       blcheck [check] [flags] -input urls.txt",
+		description: "Checks all links found on the given pages. Default command if no other is given.",
+		addFlags:    addCheckFlags,
+	}, {
+		name:        CommandCrawl,
+		usage:       "blcheck crawl [flags] <URL> [URL...]",
+		description: "Crawls all pages on the hosts of the given pages and checks every link found on them.",
+		addFlags:    addCrawlFlags,
+	}, {
+		name:        CommandFiles,
+		usage:       "blcheck files [flags] <DIRECTORY|GLOB>",
+		description: "Checks links in html, markdown, rst and text files, links to the site itself are checked on disk.",
+		addFlags:    addFilesFlags,
+	}, {
+		name:        CommandDiff,
+		usage:       "blcheck diff [flags] baseline.json current.json",
+		description: "Compares two json reports of blcheck and classifies urls as newly broken, fixed, still broken, added and removed.\nExits with an error code only if urls are newly broken.",
+		addFlags:    addDiffFlags,
+	}, {
+		name:        CommandServe,
+		usage:       "blcheck serve [flags] [URL...]",
+		description: "Serves reports of the report directory, checks of the given urls can be rerun from the browser.",
+		addFlags:    addServeFlags,
+	}, {
+		name:        CommandValidateConfig,
+		usage:       "blcheck validate-config [flags]",
+		description: "Checks the config file for unknown options, invalid values and invalid rules.",
+		addFlags:    func(fs *flag.FlagSet) { addConfigFlag(fs) },
+	}, {
+		name:        CommandVersion,
+		usage:       "blcheck version",
+		description: "Displays version of blcheck.",
+		addFlags:    func(fs *flag.FlagSet) {},
+	},
+}
+
+// Returns command with name.
+func findCommand(name string) (command, bool) {
+	for _, c := range commands {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return command{}, false
+}
+
+// Creates flag set with all flags of command.
+func newCommandFlagSet(c command) *flag.FlagSet {
+	fs := flag.NewFlagSet("blcheck "+c.name, flag.ExitOnError)
+	c.addFlags(fs)
+	addEnvironmentToUsage(fs)
+	return fs
+}
+
+// Names of all options of all commands, options of other commands are allowed in config files.
+func allOptionNames() map[string]bool {
+	names := map[string]bool{}
+	for _, c := range commands {
+		newCommandFlagSet(c).VisitAll(func(f *flag.Flag) { names[longFlagName(f.Name)] = true })
+	}
+	return names
+}
+
+// Flags of the check command.
+func addCheckFlags(fs *flag.FlagSet) {
+	addRequestFlags(fs)
+	addProgressFlag(fs)
+	addFilterFlags(fs)
+	addReportFlags(fs)
+	addOutputFlags(fs)
+	addInputFlag(fs)
+	addLocalSiteFlags(fs)
+}
+
+// Flags of the crawl command.
+func addCrawlFlags(fs *flag.FlagSet) {
+	addRequestFlags(fs)
+	addProgressFlag(fs)
+	addFilterFlags(fs)
+	addReportFlags(fs)
+	addOutputFlags(fs)
+	addInputFlag(fs)
+	intFlag(fs, &MaxCrawlDepth, "max-depth", 0, "Maximum number of links between start page and crawled pages, 0 crawls without limit")
+	intFlag(fs, &MaxCrawlPages, "max-pages", 0, "Maximum number of crawled pages, 0 crawls without limit")
+	stringFlag(fs, &CrawlStateFile, "state", "", "Saves visited pages, pending pages and results to file while crawling, the file is removed once the crawl is done")
+	stringFlag(fs, &CrawlResumeFile, "resume", "", "Continues the stopped crawl of state file, and saves its state to it")
+}
+
+// Flags of the files command.
+func addFilesFlags(fs *flag.FlagSet) {
+	addRequestFlags(fs)
+	addProgressFlag(fs)
+	addFilterFlags(fs)
+	addReportFlags(fs)
+	addOutputFlags(fs)
+	stringFlag(fs, &LocalBaseUrl, "base-url", url.DefaultLocalBaseUrl, "Base url the files are published under, used to resolve site relative links")
+}
+
+// Flags of the diff command.
+func addDiffFlags(fs *flag.FlagSet) {
+	boolFlag(fs, &OutputAsJSON, "json", "Export diff as json format")
+}
+
+// Flags of the serve command, check flags are used for checks rerun from the browser.
+func addServeFlags(fs *flag.FlagSet) {
+	addRequestFlags(fs)
+	addFilterFlags(fs)
+	addReportFlags(fs)
+	addInputFlag(fs)
+	addLocalSiteFlags(fs)
+	stringFlag(fs, &ServeAddress, "address", DefaultServeAddress, "Address the report server listens on")
+	stringFlag(fs, &ReportDirectory, "report-dir", DefaultReportDirectory, "Directory with stored reports, reports of rerun checks are stored there as well")
+}
+
+// Flags for requests of url checks.
+func addRequestFlags(fs *flag.FlagSet) {
+	intFlag(fs, &MaxParallelRequests, "max-parallel-requests", url.MaxNumParallelQueries, "Maximum number of parallel requests executed")
+	intFlag(fs, &MaxTimeoutInSeconds, "max-response-timeout", int(url.DefaultHttpGetTimeout.Seconds()), "Maximum timeout wait on requests in seconds")
+	stringFlag(fs, &CacheFile, "cache", "", "Stores results in file and reuses them in later runs while they are younger than their ttl")
+	durationFlag(fs, &CacheTTL, "cache-ttl", url.DefaultCacheTTL, "Age until cached reachable urls are revalidated, with a conditional request if the server sent an ETag or Last-Modified header")
+	durationFlag(fs, &CacheBrokenTTL, "cache-broken-ttl", url.DefaultBrokenCacheTTL, "Age until cached broken urls are checked again")
+}
+
+// Flag for the progress output of checks.
+func addProgressFlag(fs *flag.FlagSet) {
+	durationFlag(fs, &ProgressInterval, "progress-interval", DefaultProgressInterval, "Interval of progress lines on stderr, a terminal shows a live progress line instead. 0 disables progress output")
+}
+
+// Flags for which urls get checked and which documents links get extracted from.
+func addFilterFlags(fs *flag.FlagSet) {
+	funcFlag(fs, "include", "Parsed urls need to match this regular expression to get checked, can be repeated", appendTo(&RegexInclude))
+	funcFlag(fs, "exclude", "Parsed urls need to not match this regular expression to get checked, can be repeated", appendTo(&RegexExclude))
+	funcFlag(fs, "crawl-include", "Linked documents need to match this regular expression to extract their links, can be repeated", appendTo(&CrawlInclude))
+	funcFlag(fs, "crawl-exclude", "Linked documents need to not match this regular expression to extract their links, can be repeated", appendTo(&CrawlExclude))
+	boolFlag(fs, &LiteralFilters, "literal", "Matches include and exclude filters as plain substrings instead of regular expressions")
+	funcFlag(fs, "descend", "Comma separated document kinds ("+strings.Join(url.DocumentKinds, ",")+") whose links get extracted and checked as well", parseDescendInto)
+	boolFlag(fs, &ExecuteDryRun, "dry", "Only gets urls from initial webpage and does not check the status of other urls")
+}
+
+// Flags for the content of the report.
+func addReportFlags(fs *flag.FlagSet) {
+	boolFlag(fs, &ShowReachables, "show-reachable", "Includes reachable urls in report")
+	stringFlag(fs, &SortBy, "sort", "", "Sorts urls in report by one of "+strings.Join(url.SortKeys, ",")+", default is the order urls were found in")
+	intFlag(fs, &SlowestUrls, "slowest", url.DefaultSlowestUrls, "Number of slowest urls listed in the report summary")
+	stringFlag(fs, &IgnoreFile, "ignore-file", "", "File with url patterns of known broken urls, that are reported as ignored (default \""+url.DefaultIgnoreFile+"\" if it exists)")
+	addConfigFlag(fs)
+}
+
+// Flags for format and destination of the report.
+func addOutputFlags(fs *flag.FlagSet) {
+	boolFlag(fs, &OutputAsJSON, "json", "Export output as json format")
+	boolFlag(fs, &OutputAsCSV, "csv", "Export output as csv format")
+	boolFlag(fs, &OutputCsvMetaData, "csv-meta", "Adds meta information as leading # comment lines to csv output")
+	boolFlag(fs, &OutputAsHTML, "html", "Export output as self contained html page")
+	boolFlag(fs, &OutputAsJUnit, "junit", "Export output as JUnit xml, one testsuite per source page")
+	boolFlag(fs, &OutputAsSarif, "sarif", "Export broken urls as SARIF 2.1.0 for code scanning, with file and line of local documents")
+	boolFlag(fs, &OutputAsNDJSON, "ndjson", "Streams every checked url as one json line while checks run, followed by a summary line")
+	boolFlag(fs, &OutputAsMarkdown, "markdown", "Export output as markdown, e.g. for issues and wiki pages")
+	boolFlag(fs, &OutputAsGithub, "github", "Prints broken and redirected urls as GitHub Actions annotations, writes a job summary to $GITHUB_STEP_SUMMARY if set")
+	stringFlag(fs, &OutputInFile, "out", "", "Writes output to given location. If directory is given, writes to blcheck.log in directory.")
+	stringFlag(fs, &ReportDirectory, "report-dir", "", "Stores report as json, csv and html in directory, used as report history by the serve command")
+	stringFlag(fs, &BaselineFile, "baseline", "", "Compares with previous json report, only urls that broke since then fail the check")
+}
+
+// Flag for a file with additional urls to check.
+func addInputFlag(fs *flag.FlagSet) {
+	stringFlag(fs, &InputFile, "input", "", "Reads additional urls to check from file, one per line. Use - to read from stdin.")
+}
+
+// Flags for a local site that is checked together with the given urls.
+func addLocalSiteFlags(fs *flag.FlagSet) {
+	stringFlag(fs, &LocalDirectory, "directory", "", "Checks links in html, markdown, rst and text files of directory or glob, links to the site itself are checked on disk")
+	stringFlag(fs, &LocalBaseUrl, "base-url", url.DefaultLocalBaseUrl, "Base url the files of -directory are published under, used to resolve site relative links")
+}
+
+// Flag for the config file.
+func addConfigFlag(fs *flag.FlagSet) {
+	stringFlag(fs, &ConfigFile, "config", "", "Reads options, request rules per host and ignore list from yaml, toml or json file, command line flags take precedence (default "+strings.Join(config.DefaultFileNames, ", ")+" if it exists)")
+}
+
+// Defines bool flag with its short alias.
+func boolFlag(fs *flag.FlagSet, p *bool, name, usage string) {
+	for _, n := range flagNames(name) {
+		fs.BoolVar(p, n, false, usage)
+	}
+}
+
+// Defines string flag with its short alias.
+func stringFlag(fs *flag.FlagSet, p *string, name, value, usage string) {
+	for _, n := range flagNames(name) {
+		fs.StringVar(p, n, value, usage)
+	}
+}
+
+// Defines int flag with its short alias.
+func intFlag(fs *flag.FlagSet, p *int, name string, value int, usage string) {
+	for _, n := range flagNames(name) {
+		fs.IntVar(p, n, value, usage)
+	}
+}
+
+// Defines duration flag with its short alias.
+func durationFlag(fs *flag.FlagSet, p *time.Duration, name string, value time.Duration, usage string) {
+	for _, n := range flagNames(name) {
+		fs.DurationVar(p, n, value, usage)
+	}
+}
+
+// Defines func flag with its short alias.
+func funcFlag(fs *flag.FlagSet, name, usage string, fn func(string) error) {
+	for _, n := range flagNames(name) {
+		fs.Func(n, usage, fn)
+	}
+}
+
+// Long name and short alias of a flag, if it has one.
+func flagNames(name string) []string {
+	if short := shortFlagName(name); short != "" {
+		return []string{name, short}
+	}
+	return []string{name}
+}
+
+// Short alias of flag with long name, empty if it has none.
+func shortFlagName(name string) string {
+	for short, long := range longFlagNames {
+		if long == name {
+			return short
+		}
+	}
+	return ""
+}
+
+// Prints flags of fs to w, a flag and its short alias are printed once.
+func printFlags(w io.Writer, fs *flag.FlagSet) {
+	fs.VisitAll(func(f *flag.Flag) {
+		if longFlagName(f.Name) != f.Name {
+			return
+		}
+		line := "  -" + f.Name
+		if short := shortFlagName(f.Name); short != "" {
+			line += ", -" + short
+		}
+		typeName, usage := flag.UnquoteUsage(f)
+		if typeName != "" {
+			line += " " + typeName
+		}
+		line += "\n    \t" + strings.ReplaceAll(usage, "\n", "\n    \t")
+		if f.DefValue != "" && f.DefValue != "0" && f.DefValue != "false" {
+			if typeName == "string" {
+				line += fmt.Sprintf(" (default %q)", f.DefValue)
+			} else {
+				line += fmt.Sprintf(" (default %v)", f.DefValue)
+			}
+		}
+		fmt.Fprintln(w, line)
+	})
+}
@@ -0,0 +1,81 @@
+package arguments
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCommandFlagSets(t *testing.T) {
+	for _, c := range commands {
+		t.Run(c.name, func(t *testing.T) {
+			fs := newCommandFlagSet(c)
+			var output strings.Builder
+			printFlags(&output, fs)
+			for short, long := range longFlagNames {
+				if fs.Lookup(long) == nil {
+					continue
+				}
+				if fs.Lookup(short) == nil {
+					t.Errorf("expected short alias -%s of -%s", short, long)
+				}
+				lines := 0
+				for _, line := range strings.Split(output.String(), "\n") {
+					if fields := strings.Fields(line); len(fields) >= 2 && fields[0] == "-"+long+"," && fields[1] == "-"+short {
+						lines++
+					}
+				}
+				if lines != 1 {
+					t.Errorf("expected -%s and -%s to be printed once in one line", long, short)
+				}
+			}
+		})
+	}
+	t.Run("command specific flags", func(t *testing.T) {
+		crawl, _ := findCommand(CommandCrawl)
+		check, _ := findCommand(CommandCheck)
+		if newCommandFlagSet(crawl).Lookup("max-depth") == nil {
+			t.Error("expected crawl to have -max-depth")
+		}
+		if newCommandFlagSet(check).Lookup("max-depth") != nil {
+			t.Error("did not expect check to have -max-depth")
+		}
+		if !allOptionNames()["max-depth"] || !allOptionNames()["address"] {
+			t.Error("expected options of all commands to be known")
+		}
+	})
+}
+
+func TestValidateConfig(t *testing.T) {
+	t.Cleanup(func() {
+		SortBy, MaxCrawlDepth, ConfigFile = "", 0, ""
+		knownOptions = map[string]bool{}
+	})
+	knownOptions = allOptionNames()
+	dir := t.TempDir()
+	cases := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{"options of several commands", "max-depth: 2\nsort: host\naddress: localhost:9000\n", false},
+		{"unknown option", "sorty: host\n", true},
+		{"invalid value", "max-parallel-requests: many\n", true},
+		{"invalid sort order", "sort: hostx\n", true},
+		{"negative depth", "max-depth: -1\n", true},
+		{"invalid request rule", "accept:\n  - pattern: (\n", true},
+	}
+	for i, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, "blcheck"+string(rune('a'+i))+".yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := validateConfig(path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error %v, expected error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,359 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/Felixs/blcheck/pkg/url"
+	"gopkg.in/yaml.v3"
+)
+
+// Config files that are used if they exist in the working directory, in order
+var DefaultFileNames = []string{"blcheck.yaml", "blcheck.yml", "blcheck.toml", "blcheck.json"}
+
+// Header set that is sent with every request
+const DefaultHeaderSet = "default"
+
+// Top level keys that are not command line options
+var sectionKeys = []string{"urls", "headers", "hosts", "accept", "rules", "ignore"}
+
+// Content of a config file.
+type Config struct {
+	// Path of the file the config was read from
+	Path string `json:"-" yaml:"-" toml:"-"`
+	// Values of command line options by option name, lists hold one value per entry
+	Options map[string][]string `json:"-" yaml:"-" toml:"-"`
+	// Urls to check if none are given as arguments
+	Urls []string `json:"urls" yaml:"urls" toml:"urls"`
+	// Named sets of request headers, the set named default is sent with every request
+	Headers map[string]map[string]string `json:"headers" yaml:"headers" toml:"headers"`
+	// Request settings per host
+	Hosts []HostRule `json:"hosts" yaml:"hosts" toml:"hosts"`
+	// Additional accepted status codes for urls matching a pattern
+	Accept []AcceptRule `json:"accept" yaml:"accept" toml:"accept"`
+	// Request settings and actions for urls matching host and pattern
+	Rules []Rule `json:"rules" yaml:"rules" toml:"rules"`
+	// Known broken urls in the format of ignore files
+	Ignore []string `json:"ignore" yaml:"ignore" toml:"ignore"`
+}
+
+// Request settings for urls of a host.
+type HostRule struct {
+	// Hostname, *.example.com matches all subdomains
+	Host string `json:"host" yaml:"host" toml:"host"`
+	// Names of header sets sent to the host
+	HeaderSets []string `json:"header_sets" yaml:"header_sets" toml:"header_sets"`
+	// Headers sent to the host, overwrite headers of header sets
+	Headers map[string]string `json:"headers" yaml:"headers" toml:"headers"`
+	// Status codes besides 200 that count as reachable
+	Accept []int `json:"accept" yaml:"accept" toml:"accept"`
+	// Timeout of requests in seconds
+	Timeout int `json:"timeout" yaml:"timeout" toml:"timeout"`
+}
+
+// Request settings and action for urls of a host that match a regular expression.
+type Rule struct {
+	// Recorded in the report for matching urls
+	Name string `json:"name" yaml:"name" toml:"name"`
+	// Hostname, *.example.com matches all subdomains, empty matches all hosts
+	Host string `json:"host" yaml:"host" toml:"host"`
+	// Regular expression urls need to match, empty matches all urls
+	Pattern    string            `json:"pattern" yaml:"pattern" toml:"pattern"`
+	HeaderSets []string          `json:"header_sets" yaml:"header_sets" toml:"header_sets"`
+	Headers    map[string]string `json:"headers" yaml:"headers" toml:"headers"`
+	Accept     []int             `json:"accept" yaml:"accept" toml:"accept"`
+	// Timeout of requests in seconds
+	Timeout int `json:"timeout" yaml:"timeout" toml:"timeout"`
+	// HEAD or GET
+	Method string `json:"method" yaml:"method" toml:"method"`
+	// Number of repeated requests if the url is broken
+	Retries *int `json:"retries" yaml:"retries" toml:"retries"`
+	// skip or ignore, matching urls are checked if empty
+	Action string `json:"action" yaml:"action" toml:"action"`
+}
+
+// Accepted status codes for urls matching a regular expression.
+type AcceptRule struct {
+	Pattern     string `json:"pattern" yaml:"pattern" toml:"pattern"`
+	StatusCodes []int  `json:"status_codes" yaml:"status_codes" toml:"status_codes"`
+}
+
+// Returns the first default config file that exists in dir.
+func Discover(dir string) (path string, found bool) {
+	for _, name := range DefaultFileNames {
+		path := filepath.Join(dir, name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// Reads config file at path, the format is chosen by the file extension.
+func Load(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("could not read config file: %w", err)
+	}
+	config, err := Parse(data, strings.TrimPrefix(filepath.Ext(path), "."))
+	if err != nil {
+		return Config{}, fmt.Errorf("%s: %w", path, err)
+	}
+	config.Path = path
+	return config, nil
+}
+
+// Parses config in format yaml, yml, toml or json.
+func Parse(data []byte, format string) (Config, error) {
+	config := Config{}
+	var values map[string]any
+	var err error
+	tag := strings.ToLower(format)
+	switch tag {
+	case "yaml", "yml":
+		tag = "yaml"
+		if err = yaml.Unmarshal(data, &values); err == nil {
+			err = yaml.Unmarshal(data, &config)
+		}
+	case "toml":
+		if _, err = toml.Decode(string(data), &values); err == nil {
+			_, err = toml.Decode(string(data), &config)
+		}
+	case "json":
+		if err = json.Unmarshal(data, &values); err == nil {
+			if err = json.Unmarshal(data, &config); err != nil {
+				err = sectionError(err)
+			}
+		}
+	default:
+		return Config{}, fmt.Errorf("unknown config format %q, use yaml, toml or json", format)
+	}
+	if err != nil {
+		return Config{}, err
+	}
+	config.Options, err = options(values, tag)
+	if err != nil {
+		return Config{}, err
+	}
+	return config, nil
+}
+
+// Returns command line options of the top level values and checks that sections only use known keys.
+func options(values map[string]any, tag string) (map[string][]string, error) {
+	options := map[string][]string{}
+	for key, value := range values {
+		if slices.Contains(sectionKeys, key) {
+			if unknown := unknownKey(value, sectionType(key), tag, key); unknown != "" {
+				return nil, fmt.Errorf("unknown key %q", unknown)
+			}
+			continue
+		}
+		optionValues, err := toOptionValues(value)
+		if err != nil {
+			return nil, fmt.Errorf("option %q: %w", key, err)
+		}
+		options[strings.ReplaceAll(strings.ToLower(key), "_", "-")] = optionValues
+	}
+	return options, nil
+}
+
+// Returns the type of the Config field holding section key.
+func sectionType(key string) reflect.Type {
+	field, _ := fieldByTag(reflect.TypeOf(Config{}), "json", key)
+	return field.Type
+}
+
+// Returns the path of the first key in value that has no field in t, fields are matched by their tag.
+func unknownKey(value any, t reflect.Type, tag string, path string) string {
+	v := reflect.ValueOf(value)
+	switch t.Kind() {
+	case reflect.Pointer:
+		return unknownKey(value, t.Elem(), tag, path)
+	case reflect.Slice:
+		if v.Kind() != reflect.Slice {
+			return ""
+		}
+		for i := range v.Len() {
+			if unknown := unknownKey(v.Index(i).Interface(), t.Elem(), tag, path+"."+strconv.Itoa(i)); unknown != "" {
+				return unknown
+			}
+		}
+	case reflect.Map, reflect.Struct:
+		if v.Kind() != reflect.Map {
+			return ""
+		}
+		keys := v.MapKeys()
+		slices.SortFunc(keys, func(a, b reflect.Value) int {
+			return strings.Compare(fmt.Sprint(a.Interface()), fmt.Sprint(b.Interface()))
+		})
+		for _, key := range keys {
+			name := fmt.Sprint(key.Interface())
+			elem := t
+			if t.Kind() == reflect.Map {
+				elem = t.Elem()
+			} else if field, ok := fieldByTag(t, tag, name); ok {
+				elem = field.Type
+			} else {
+				return path + "." + name
+			}
+			if unknown := unknownKey(v.MapIndex(key).Interface(), elem, tag, path+"."+name); unknown != "" {
+				return unknown
+			}
+		}
+	}
+	return ""
+}
+
+// Returns the field of struct t whose tag names key.
+func fieldByTag(t reflect.Type, tag string, key string) (reflect.StructField, bool) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if name, _, _ := strings.Cut(field.Tag.Get(tag), ","); name == key {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// Rewrites errors of decoding sections to name the config key instead of the Go struct field.
+func sectionError(err error) error {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return fmt.Errorf("key %q: expected %s, got %s", typeErr.Field, typeDescription(typeErr.Type), typeErr.Value)
+	}
+	return errors.New(strings.TrimPrefix(err.Error(), "json: "))
+}
+
+// Describes the kind of value expected for a config key.
+func typeDescription(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Slice:
+		return "a list"
+	case reflect.Map, reflect.Struct:
+		return "a mapping"
+	case reflect.Int, reflect.Int64:
+		return "a number"
+	default:
+		return "a " + t.Kind().String()
+	}
+}
+
+// Converts scalar or list of scalars to option values.
+func toOptionValues(value any) ([]string, error) {
+	list, isList := value.([]any)
+	if !isList {
+		list = []any{value}
+	}
+	optionValues := []string{}
+	for _, v := range list {
+		s, ok := scalarString(v)
+		if !ok {
+			return nil, errors.New("expected a value or a list of values")
+		}
+		optionValues = append(optionValues, s)
+	}
+	return optionValues, nil
+}
+
+// Converts string, bool or number to its string form.
+func scalarString(value any) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case bool:
+		return strconv.FormatBool(v), true
+	case int:
+		return strconv.Itoa(v), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	}
+	return "", false
+}
+
+// Creates request rules from the default header set, host rules, accept rules and rules, in this order.
+func (c Config) RequestRules() ([]url.RequestRule, error) {
+	rules := []url.RequestRule{}
+	if headers, ok := c.Headers[DefaultHeaderSet]; ok {
+		rule, _ := url.NewRequestRule("", "")
+		rule.Name = "headers " + DefaultHeaderSet
+		rule.Headers = headers
+		rules = append(rules, rule)
+	}
+	for _, host := range c.Hosts {
+		if host.Host == "" {
+			return nil, errors.New("host rule without host")
+		}
+		rule, err := c.requestRule(Rule{Host: host.Host, HeaderSets: host.HeaderSets, Headers: host.Headers, Accept: host.Accept, Timeout: host.Timeout})
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	for _, accept := range c.Accept {
+		if accept.Pattern == "" {
+			return nil, errors.New("accept rule without pattern")
+		}
+		rule, err := url.NewRequestRule("", accept.Pattern)
+		if err != nil {
+			return nil, err
+		}
+		rule.AcceptedStatusCodes = accept.StatusCodes
+		rules = append(rules, rule)
+	}
+	for _, r := range c.Rules {
+		rule, err := c.requestRule(r)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// Creates request rule with the headers of its header sets, headers of the rule overwrite them.
+func (c Config) requestRule(r Rule) (url.RequestRule, error) {
+	rule, err := url.NewRequestRule(r.Host, r.Pattern)
+	if err != nil {
+		return url.RequestRule{}, err
+	}
+	rule.Name = r.Name
+	rule.Headers = map[string]string{}
+	for _, name := range r.HeaderSets {
+		headers, ok := c.Headers[name]
+		if !ok {
+			return url.RequestRule{}, fmt.Errorf("rule %s uses unknown header set %q", rule, name)
+		}
+		for k, v := range headers {
+			rule.Headers[k] = v
+		}
+	}
+	for k, v := range r.Headers {
+		rule.Headers[k] = v
+	}
+	rule.AcceptedStatusCodes = r.Accept
+	rule.Timeout = time.Duration(r.Timeout) * time.Second
+	rule.Method = strings.ToUpper(r.Method)
+	rule.Retries = r.Retries
+	rule.Action = url.RuleAction(strings.ToLower(r.Action))
+	return rule, rule.Validate()
+}
+
+// Parses the ignore list like the lines of an ignore file.
+func (c Config) IgnoreRules() (url.IgnoreRules, error) {
+	rules, err := url.ParseIgnoreRules(strings.NewReader(strings.Join(c.Ignore, "\n")))
+	if err != nil {
+		return nil, fmt.Errorf("ignore list: %w", err)
+	}
+	return rules, nil
+}
@@ -0,0 +1,294 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Felixs/blcheck/pkg/url"
+)
+
+const yamlConfig = `# blcheck configuration
+max-parallel-requests: 10
+show_reachable: true
+include:
+  - '^https://(www\.)?example\.com'
+  - "docs\\."
+descend: [html, pdf]
+urls:
+- https://example.com
+headers:
+  browser:
+    User-Agent: "Mozilla/5.0 (X11; Linux x86_64)"
+  default:
+    Accept-Language: en
+hosts:
+  - host: api.example.com
+    accept: [401, 403]
+    header_sets: [browser]
+    timeout: 20
+  - host: "*.cdn.example.com"
+    headers:
+      X-Token: secret # not checked in
+accept:
+  - pattern: ^https://github\.com/.*/edit/
+    status_codes: [429]
+ignore:
+  - "https://www.linkedin.com/* # blocks requests from CI"
+`
+
+const tomlConfig = `# blcheck configuration
+max-parallel-requests = 10
+show_reachable = true
+include = [
+  '^https://(www\.)?example\.com',
+  "docs\\.",
+]
+descend = ["html", "pdf"]
+urls = ["https://example.com"]
+ignore = ["https://www.linkedin.com/* # blocks requests from CI"]
+
+[headers.browser]
+User-Agent = "Mozilla/5.0 (X11; Linux x86_64)"
+
+[headers.default]
+Accept-Language = "en"
+
+[[hosts]]
+host = "api.example.com"
+accept = [401, 403]
+header_sets = ["browser"]
+timeout = 20
+
+[[hosts]]
+host = "*.cdn.example.com"
+headers = { X-Token = "secret" } # not checked in
+
+[[accept]]
+pattern = '^https://github\.com/.*/edit/'
+status_codes = [429]
+`
+
+const jsonConfig = `{
+  "max-parallel-requests": 10,
+  "show_reachable": true,
+  "include": ["^https://(www\\.)?example\\.com", "docs\\."],
+  "descend": ["html", "pdf"],
+  "urls": ["https://example.com"],
+  "headers": {
+    "browser": {"User-Agent": "Mozilla/5.0 (X11; Linux x86_64)"},
+    "default": {"Accept-Language": "en"}
+  },
+  "hosts": [
+    {"host": "api.example.com", "accept": [401, 403], "header_sets": ["browser"], "timeout": 20},
+    {"host": "*.cdn.example.com", "headers": {"X-Token": "secret"}}
+  ],
+  "accept": [{"pattern": "^https://github\\.com/.*/edit/", "status_codes": [429]}],
+  "ignore": ["https://www.linkedin.com/* # blocks requests from CI"]
+}`
+
+func TestParse(t *testing.T) {
+	wantOptions := map[string][]string{
+		"max-parallel-requests": {"10"},
+		"show-reachable":        {"true"},
+		"include":               {`^https://(www\.)?example\.com`, `docs\.`},
+		"descend":               {"html", "pdf"},
+	}
+	wantHosts := []HostRule{
+		{Host: "api.example.com", Accept: []int{401, 403}, HeaderSets: []string{"browser"}, Timeout: 20},
+		{Host: "*.cdn.example.com", Headers: map[string]string{"X-Token": "secret"}},
+	}
+	for format, document := range map[string]string{"yaml": yamlConfig, "toml": tomlConfig, "json": jsonConfig} {
+		t.Run(format, func(t *testing.T) {
+			config, err := Parse([]byte(document), format)
+			if err != nil {
+				t.Fatalf("did not expect an error, got %v", err)
+			}
+			if !reflect.DeepEqual(config.Options, wantOptions) {
+				t.Errorf("got options %v expected %v", config.Options, wantOptions)
+			}
+			if !reflect.DeepEqual(config.Urls, []string{"https://example.com"}) {
+				t.Errorf("got urls %v", config.Urls)
+			}
+			if !reflect.DeepEqual(config.Hosts, wantHosts) {
+				t.Errorf("got hosts %+v expected %+v", config.Hosts, wantHosts)
+			}
+			if config.Headers["browser"]["User-Agent"] != "Mozilla/5.0 (X11; Linux x86_64)" {
+				t.Errorf("got header sets %v", config.Headers)
+			}
+			if len(config.Accept) != 1 || config.Accept[0].Pattern != `^https://github\.com/.*/edit/` {
+				t.Errorf("got accept rules %+v", config.Accept)
+			}
+			if len(config.Ignore) != 1 {
+				t.Errorf("got ignore list %v", config.Ignore)
+			}
+		})
+	}
+	t.Run("unknown field in section", func(t *testing.T) {
+		documents := map[string]string{
+			"yaml": "hosts:\n  - host: a.de\n    timout: 20\n",
+			"toml": "[[hosts]]\nhost = \"a.de\"\ntimout = 20\n",
+			"json": `{"hosts": [{"host": "a.de", "timout": 20}]}`,
+		}
+		for format, document := range documents {
+			_, err := Parse([]byte(document), format)
+			if want := `unknown key "hosts.0.timout"`; err == nil || err.Error() != want {
+				t.Errorf("got error %v for %s expected %q", err, format, want)
+			}
+		}
+	})
+	t.Run("scalar header values", func(t *testing.T) {
+		document := "headers:\n  default:\n    X-Flag: true\nhosts:\n  - host: a.de\n    headers:\n      X-Version: 2\n"
+		config, err := Parse([]byte(document), "yaml")
+		if err != nil {
+			t.Fatalf("did not expect an error, got %v", err)
+		}
+		if config.Headers[DefaultHeaderSet]["X-Flag"] != "true" || config.Hosts[0].Headers["X-Version"] != "2" {
+			t.Errorf("got header sets %v and hosts %+v", config.Headers, config.Hosts)
+		}
+	})
+	t.Run("wrong type names location", func(t *testing.T) {
+		documents := map[string][]string{
+			"yaml": {"hosts:\n  - host: a.de\n    timeout: slow\n", "line 3"},
+			"toml": {"[[hosts]]\nhost = \"a.de\"\ntimeout = \"slow\"\n", `"hosts.timeout"`},
+			// Older Go versions leave out the list index of the key
+			"json": {`{"hosts": [{"host": "a.de", "timeout": "slow"}]}`, `timeout": expected a number, got string`},
+		}
+		for format, document := range documents {
+			_, err := Parse([]byte(document[0]), format)
+			if err == nil || !strings.Contains(err.Error(), document[1]) {
+				t.Errorf("got error %v for %s expected it to contain %q", err, format, document[1])
+			}
+		}
+	})
+	t.Run("yaml and toml syntax", func(t *testing.T) {
+		documents := map[string]string{
+			"yaml": "color: yes\nheaders: {default: {X-Token: secret}}\nignore:\n  - |-\n    https://a.de/*\n    # reason\n  - 'https://b.de/ # quoted'\n",
+			"toml": "color = \"yes\"\nheaders = { default = { X-Token = \"secret\" } }\nignore = [\"\"\"https://a.de/*\n# reason\"\"\", 'https://b.de/ # quoted']\n",
+		}
+		for format, document := range documents {
+			config, err := Parse([]byte(document), format)
+			if err != nil {
+				t.Fatalf("did not expect an error for %s, got %v", format, err)
+			}
+			wantIgnore := []string{"https://a.de/*\n# reason", "https://b.de/ # quoted"}
+			if !reflect.DeepEqual(config.Ignore, wantIgnore) || config.Headers[DefaultHeaderSet]["X-Token"] != "secret" || config.Options["color"][0] != "yes" {
+				t.Errorf("got ignore list %q, header sets %v and options %v for %s", config.Ignore, config.Headers, config.Options, format)
+			}
+		}
+	})
+	t.Run("unknown format", func(t *testing.T) {
+		if _, err := Parse([]byte(""), "ini"); err == nil {
+			t.Error("expected an error")
+		}
+	})
+	t.Run("nested option value", func(t *testing.T) {
+		if _, err := Parse([]byte(`{"sort": {"by": "url"}}`), "json"); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
+
+func TestRequestRules(t *testing.T) {
+	config, err := Parse([]byte(yamlConfig), "yaml")
+	if err != nil {
+		t.Fatalf("did not expect an error, got %v", err)
+	}
+	rules, err := config.RequestRules()
+	if err != nil {
+		t.Fatalf("did not expect an error, got %v", err)
+	}
+	if len(rules) != 4 {
+		t.Fatalf("got %d rules expected 4", len(rules))
+	}
+	if !rules[0].Matches("https://any.de") || rules[0].Headers["Accept-Language"] != "en" {
+		t.Errorf("expected default header set to match every url, got %+v", rules[0])
+	}
+	api := rules[1]
+	if api.Headers["User-Agent"] == "" || api.Timeout != 20*time.Second || !reflect.DeepEqual(api.AcceptedStatusCodes, []int{401, 403}) {
+		t.Errorf("got api rule %+v", api)
+	}
+	if !rules[2].Matches("https://img.cdn.example.com/a.png") || rules[2].Headers["X-Token"] != "secret" {
+		t.Errorf("got cdn rule %+v", rules[2])
+	}
+	if !rules[3].Matches("https://github.com/a/b/edit/main/README.md") {
+		t.Errorf("expected accept rule to match edit urls")
+	}
+
+	config.Hosts[0].HeaderSets = []string{"missing"}
+	if _, err := config.RequestRules(); err == nil {
+		t.Error("expected an error for unknown header set")
+	}
+}
+
+func TestIgnoreRules(t *testing.T) {
+	config := Config{Ignore: []string{"https://www.linkedin.com/* # blocks requests from CI", "https://old.de 2024-12-31"}}
+	rules, err := config.IgnoreRules()
+	if err != nil {
+		t.Fatalf("did not expect an error, got %v", err)
+	}
+	if len(rules) != 2 || rules[0].Reason != "blocks requests from CI" {
+		t.Errorf("got rules %v", rules)
+	}
+	config.Ignore = []string{"https://old.de 31.12.2024"}
+	if _, err := config.IgnoreRules(); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	dir := t.TempDir()
+	if _, found := Discover(dir); found {
+		t.Error("did not expect a config file in empty directory")
+	}
+	for _, name := range []string{"blcheck.json", "blcheck.toml"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if path, found := Discover(dir); !found || filepath.Base(path) != "blcheck.toml" {
+		t.Errorf("got %q expected blcheck.toml to be preferred", path)
+	}
+}
+
+func TestRules(t *testing.T) {
+	config, err := Parse([]byte(`headers:
+  browser:
+    User-Agent: Mozilla/5.0
+rules:
+  - name: social
+    host: "*.linkedin.com"
+    header_sets: [browser]
+    method: get
+    retries: 2
+  - pattern: ^https://example\.com/private/
+    action: skip
+`), "yaml")
+	if err != nil {
+		t.Fatalf("did not expect an error, got %v", err)
+	}
+	rules, err := config.RequestRules()
+	if err != nil {
+		t.Fatalf("did not expect an error, got %v", err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("got %d rules expected 2", len(rules))
+	}
+	social := rules[0]
+	if social.Name != "social" || social.Method != "GET" || social.Retries == nil || *social.Retries != 2 || social.Headers["User-Agent"] != "Mozilla/5.0" {
+		t.Errorf("got rule %+v", social)
+	}
+	if rules[1].Action != url.ActionSkip || !rules[1].Matches("https://example.com/private/a") {
+		t.Errorf("got rule %+v", rules[1])
+	}
+
+	for _, invalid := range []Rule{{Action: "drop"}, {Method: "POST"}, {Pattern: "("}, {HeaderSets: []string{"missing"}}} {
+		config.Rules = []Rule{invalid}
+		if _, err := config.RequestRules(); err == nil {
+			t.Errorf("expected an error for %+v", invalid)
+		}
+	}
+}
@@ -13,4 +13,7 @@ const (
 	ExitToManyOutputFormats              int = 8
 	ExitInvalidNumberMaxParallelRequests int = 9
 	ExitInlvaidNumberMaxTimeoutInSeconds int = 10
+	ExitFailedToReadInput                int = 11
+	ExitFailedToServe                    int = 12
+	ExitCrawlStopped                     int = 13
 )
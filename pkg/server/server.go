@@ -0,0 +1,183 @@
+/*
+Package server serves stored blcheck reports in the browser.
+*/
+package server
+
+import (
+	"html/template"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Felixs/blcheck/pkg/url"
+)
+
+// Runs a new check and returns its report.
+type RunFunc func() (url.UrlReport, error)
+
+// Valid report names, protects against paths outside of the report directory
+var reportNameRegex = regexp.MustCompile(`^` + regexp.QuoteMeta(url.ReportFilePrefix) + `[0-9T]+$`)
+
+// Webserver for reports in a report directory.
+type ReportServer struct {
+	ReportDirectory string
+	rerun           RunFunc
+
+	mu        sync.Mutex
+	running   bool
+	lastError string
+}
+
+// Creates ReportServer for reports in reportDirectory. Checks can be rerun from the browser if rerun is not nil.
+func New(reportDirectory string, rerun RunFunc) *ReportServer {
+	return &ReportServer{ReportDirectory: reportDirectory, rerun: rerun}
+}
+
+// Serves reports on address until the server fails.
+func (s *ReportServer) ListenAndServe(address string) error {
+	return http.ListenAndServe(address, s.Handler())
+}
+
+// Routes of the report server.
+func (s *ReportServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.handleIndex)
+	mux.HandleFunc("GET /reports/{file}", s.handleReport)
+	mux.HandleFunc("POST /run", s.handleRun)
+	return mux
+}
+
+// Lists names of all stored reports, newest first.
+func (s *ReportServer) ListReports() ([]string, error) {
+	entries, err := os.ReadDir(s.ReportDirectory)
+	if os.IsNotExist(err) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".json")
+		if !entry.IsDir() && name != entry.Name() && reportNameRegex.MatchString(name) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	slices.Reverse(names)
+	return names, nil
+}
+
+// Data shown on the index page
+type indexPage struct {
+	Latest    string
+	Reports   []string
+	CanRerun  bool
+	Running   bool
+	LastError string
+}
+
+// Shows latest report and history of all reports.
+func (s *ReportServer) handleIndex(w http.ResponseWriter, r *http.Request) {
+	reports, err := s.ListReports()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	s.mu.Lock()
+	page := indexPage{Reports: reports, CanRerun: s.rerun != nil, Running: s.running, LastError: s.lastError}
+	s.mu.Unlock()
+	if len(reports) > 0 {
+		page.Latest = reports[0]
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := indexTemplate.Execute(w, page); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// Serves one report file, json and csv are offered as download.
+func (s *ReportServer) handleReport(w http.ResponseWriter, r *http.Request) {
+	file := r.PathValue("file")
+	ext := filepath.Ext(file)
+	if !slices.Contains(url.ReportFileExtensions, ext) || !reportNameRegex.MatchString(strings.TrimSuffix(file, ext)) {
+		http.NotFound(w, r)
+		return
+	}
+	if ext != ".html" {
+		w.Header().Set("Content-Disposition", `attachment; filename="`+file+`"`)
+	}
+	http.ServeFile(w, r, filepath.Join(s.ReportDirectory, file))
+}
+
+// Starts a new check in the background and returns to the index page.
+func (s *ReportServer) handleRun(w http.ResponseWriter, r *http.Request) {
+	if s.rerun == nil {
+		http.Error(w, "no urls to check were given on startup", http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	if !s.running {
+		s.running = true
+		go s.runCheck()
+	}
+	s.mu.Unlock()
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+// Runs check and stores its report in the report directory.
+func (s *ReportServer) runCheck() {
+	report, err := s.rerun()
+	if err == nil {
+		_, err = url.WriteReportFiles(s.ReportDirectory, report)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.running = false
+	s.lastError = ""
+	if err != nil {
+		s.lastError = time.Now().Format(time.RFC3339) + ": " + err.Error()
+	}
+}
+
+var indexTemplate = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>blcheck reports</title>
+{{if .Running}}<meta http-equiv="refresh" content="3">{{end}}
+<style>
+body { font-family: system-ui, sans-serif; margin: 0; display: flex; height: 100vh; color: #222; }
+nav { width: 22em; padding: 1em; border-right: 1px solid #ddd; overflow-y: auto; background: #fafafa; }
+main { flex: 1; }
+iframe { border: 0; width: 100%; height: 100%; }
+li { margin: 0.4em 0; }
+.error { color: #c0392b; }
+button { padding: 0.5em 1em; }
+</style>
+</head>
+<body>
+<nav>
+<h1>blcheck reports</h1>
+{{if .CanRerun}}<form method="post" action="/run">
+<button type="submit"{{if .Running}} disabled{{end}}>{{if .Running}}Check running...{{else}}Run check{{end}}</button>
+</form>{{end}}
+{{if .LastError}}<p class="error">{{.LastError}}</p>{{end}}
+<h2>History</h2>
+{{if .Reports}}<ul>
+{{range .Reports}}<li><a href="/reports/{{.}}.html" target="report">{{.}}</a><br>
+<a href="/reports/{{.}}.json">json</a> | <a href="/reports/{{.}}.csv">csv</a></li>
+{{end}}</ul>{{else}}<p>No reports yet.</p>{{end}}
+</nav>
+<main>
+{{if .Latest}}<iframe name="report" src="/reports/{{.Latest}}.html"></iframe>{{end}}
+</main>
+</body>
+</html>
+`))
@@ -0,0 +1,128 @@
+package server
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Felixs/blcheck/pkg/url"
+)
+
+func TestReportServer(t *testing.T) {
+	dir := t.TempDir()
+	first := url.NewUrlReport(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Second, []url.UrlStatus{{Url: "https://www.google.de", StatusMessage: "Not Found"}})
+	second := url.NewUrlReport(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), time.Second, []url.UrlStatus{})
+	firstName, _ := url.WriteReportFiles(dir, first)
+	secondName, _ := url.WriteReportFiles(dir, second)
+
+	rerunReport := url.NewUrlReport(time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC), time.Second, []url.UrlStatus{})
+	s := New(dir, func() (url.UrlReport, error) { return rerunReport, nil })
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+
+	t.Run("lists reports newest first", func(t *testing.T) {
+		got, err := s.ListReports()
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if len(got) != 2 || got[0] != secondName || got[1] != firstName {
+			t.Errorf("got %v want [%s %s]", got, secondName, firstName)
+		}
+	})
+
+	t.Run("index shows latest report and history", func(t *testing.T) {
+		status, body := get(t, ts.URL+"/")
+		if status != http.StatusOK {
+			t.Fatalf("got status %d", status)
+		}
+		for _, want := range []string{`src="/reports/` + secondName + `.html"`, `href="/reports/` + firstName + `.json"`, "Run check"} {
+			if !strings.Contains(body, want) {
+				t.Errorf("expected index to contain %q", want)
+			}
+		}
+	})
+
+	t.Run("serves report as json download", func(t *testing.T) {
+		resp, err := http.Get(ts.URL + "/reports/" + firstName + ".json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		if !strings.Contains(string(body), `"url":"https://www.google.de"`) {
+			t.Errorf("got unexpected json %s", body)
+		}
+		if !strings.HasPrefix(resp.Header.Get("Content-Disposition"), "attachment") {
+			t.Errorf("expected json to be a download")
+		}
+	})
+
+	t.Run("rejects files that are no reports", func(t *testing.T) {
+		for _, file := range []string{"secret.json", firstName + ".txt", "..%2f" + firstName + ".json"} {
+			status, _ := get(t, ts.URL+"/reports/"+file)
+			if status != http.StatusNotFound {
+				t.Errorf("got status %d for %s, want 404", status, file)
+			}
+		}
+	})
+
+	t.Run("rerun stores new report", func(t *testing.T) {
+		resp, err := http.Post(ts.URL+"/run", "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		deadline := time.Now().Add(time.Second)
+		for time.Now().Before(deadline) {
+			reports, _ := s.ListReports()
+			if len(reports) == 3 {
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Errorf("expected rerun to store a third report")
+	})
+}
+
+func TestReportServerWithoutRerun(t *testing.T) {
+	ts := httptest.NewServer(New(t.TempDir(), nil).Handler())
+	defer ts.Close()
+
+	status, body := get(t, ts.URL+"/")
+	if status != http.StatusOK || strings.Contains(body, "Run check") || !strings.Contains(body, "No reports yet.") {
+		t.Errorf("got status %d and unexpected index %s", status, body)
+	}
+	resp, err := http.Post(ts.URL+"/run", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("got status %d want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+}
+
+func TestReportServerRerunError(t *testing.T) {
+	s := New(t.TempDir(), func() (url.UrlReport, error) { return url.UrlReport{}, errors.New("page not reachable") })
+	s.running = true
+	s.runCheck()
+	if s.running || !strings.HasSuffix(s.lastError, "page not reachable") {
+		t.Errorf("expected failed run to be recorded, got running=%v lastError=%q", s.running, s.lastError)
+	}
+}
+
+// Gets url and returns status code and body.
+func get(t *testing.T, u string) (int, string) {
+	t.Helper()
+	resp, err := http.Get(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body)
+}
@@ -0,0 +1,137 @@
+package url
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Characters that can be used to underline reStructuredText section titles
+const rstUnderlineChars = "=-~^\"'`#*+:.<>_"
+
+var (
+	// Markdown ATX headings: ## Title ##
+	markdownHeadingRegex = regexp.MustCompile(`^ {0,3}#{1,6}\s+(.*?)(?:\s+#+)?\s*$`)
+	// Markdown setext heading underlines: === or ---
+	markdownSetextUnderlineRegex = regexp.MustCompile(`^ {0,3}(?:=+|-+)\s*$`)
+	// Markdown links and images in headings, only the text is part of the anchor
+	markdownHeadingLinkRegex = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+	// Html id and name attributes
+	htmlAnchorRegex = regexp.MustCompile(`(?i)\s(?:id|name)\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	// reStructuredText internal targets: .. _label:
+	rstTargetRegex = regexp.MustCompile(`^\s*\.\.\s+_([^:]+):\s*$`)
+	// Runs of characters that are not allowed in reStructuredText ids
+	rstIdInvalidRegex = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// Extracts anchors that can be linked to in a document. Returns false if anchors of the file type are unknown.
+func ExtractAnchors(file, content string) (anchors map[string]bool, supported bool) {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".md", ".markdown":
+		return extractMarkdownAnchors(content), true
+	case ".html", ".htm":
+		return extractHtmlAnchors(content), true
+	case ".rst":
+		return extractRstAnchors(content), true
+	}
+	return nil, false
+}
+
+// Creates the anchor GitHub generates for a markdown heading.
+func GithubSlug(heading string) string {
+	heading = markdownHeadingLinkRegex.ReplaceAllString(heading, "$1")
+	var builder strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(heading)) {
+		switch {
+		case r == ' ':
+			builder.WriteRune('-')
+		case r == '-' || r == '_' || unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.Is(unicode.Mn, r):
+			builder.WriteRune(r)
+		}
+	}
+	return builder.String()
+}
+
+// Anchors of all headings and html anchors in markdown, duplicated headings get numbered like on GitHub.
+func extractMarkdownAnchors(content string) map[string]bool {
+	anchors := extractHtmlAnchors(content)
+	slugCounts := make(map[string]int)
+	addHeading := func(heading string) {
+		slug := GithubSlug(heading)
+		if count := slugCounts[slug]; count > 0 {
+			anchors[fmt.Sprintf("%s-%d", slug, count)] = true
+		} else {
+			anchors[slug] = true
+		}
+		slugCounts[slug] += 1
+	}
+
+	inCodeFence := false
+	previousLine := ""
+	for _, line := range strings.Split(content, "\n") {
+		trimmedLine := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmedLine, "```") || strings.HasPrefix(trimmedLine, "~~~") {
+			inCodeFence = !inCodeFence
+			previousLine = ""
+			continue
+		}
+		if inCodeFence {
+			continue
+		}
+		if match := markdownHeadingRegex.FindStringSubmatch(line); match != nil {
+			addHeading(match[1])
+			line = ""
+		} else if strings.TrimSpace(previousLine) != "" && markdownSetextUnderlineRegex.MatchString(line) {
+			addHeading(previousLine)
+		}
+		previousLine = line
+	}
+	return anchors
+}
+
+// Anchors of all id and name attributes in html.
+func extractHtmlAnchors(content string) map[string]bool {
+	anchors := make(map[string]bool)
+	for _, match := range htmlAnchorRegex.FindAllStringSubmatch(content, -1) {
+		if anchor := match[1] + match[2]; anchor != "" {
+			anchors[anchor] = true
+		}
+	}
+	return anchors
+}
+
+// Anchors of all section titles and internal targets in reStructuredText.
+func extractRstAnchors(content string) map[string]bool {
+	anchors := make(map[string]bool)
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		if match := rstTargetRegex.FindStringSubmatch(line); match != nil {
+			anchors[rstId(match[1])] = true
+			continue
+		}
+		if i == 0 || !isRstUnderline(line) {
+			continue
+		}
+		title := strings.TrimSpace(lines[i-1])
+		if title != "" && len(strings.TrimSpace(line)) >= len(title) && !isRstUnderline(title) {
+			anchors[rstId(title)] = true
+		}
+	}
+	return anchors
+}
+
+// Checks if line only consists of one repeated punctuation character, like section underlines do.
+func isRstUnderline(line string) bool {
+	line = strings.TrimRight(line, " \t")
+	if len(line) < 2 || !strings.ContainsRune(rstUnderlineChars, rune(line[0])) {
+		return false
+	}
+	return strings.Count(line, line[:1]) == len(line)
+}
+
+// Creates the id docutils generates for a section title or target name.
+func rstId(name string) string {
+	return strings.Trim(rstIdInvalidRegex.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
@@ -0,0 +1,75 @@
+package url
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGithubSlug(t *testing.T) {
+	cases := []struct {
+		heading string
+		want    string
+	}{
+		{"Install", "install"},
+		{"Getting Started", "getting-started"},
+		{"What's new in v1.2?", "whats-new-in-v12"},
+		{"Use `blcheck --dir`", "use-blcheck---dir"},
+		{"See [the docs](https://example.com)", "see-the-docs"},
+		{"snake_case & more", "snake_case--more"},
+		{"Über Größe", "über-größe"},
+	}
+	for _, tt := range cases {
+		t.Run(tt.heading, func(t *testing.T) {
+			if got := GithubSlug(tt.heading); got != tt.want {
+				t.Errorf("got %q want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractAnchors(t *testing.T) {
+	cases := []struct {
+		name      string
+		file      string
+		content   string
+		want      map[string]bool
+		supported bool
+	}{
+		{
+			"markdown headings with duplicates, setext headings and html anchors",
+			"README.md",
+			"# Usage\n## Usage ##\nSetup\n-----\n<a name=\"custom\"></a>\n```\n# not a heading\n```",
+			map[string]bool{"usage": true, "usage-1": true, "setup": true, "custom": true},
+			true,
+		}, {
+			"html ids and names",
+			"index.html",
+			`<h1 id="top">Top</h1><a name='bottom'></a>`,
+			map[string]bool{"top": true, "bottom": true},
+			true,
+		}, {
+			"rst section titles and targets",
+			"index.rst",
+			"Getting Started\n===============\n\n.. _install-guide:\n\nShort\n==",
+			map[string]bool{"getting-started": true, "install-guide": true},
+			true,
+		}, {
+			"text files have no anchors",
+			"notes.txt",
+			"# Heading",
+			nil,
+			false,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, supported := ExtractAnchors(tt.file, tt.content)
+			if supported != tt.supported {
+				t.Fatalf("got supported %v want %v", supported, tt.supported)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v want %v", got, tt.want)
+			}
+		})
+	}
+}
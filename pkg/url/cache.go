@@ -0,0 +1,142 @@
+package url
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// Age until reachable results get revalidated
+	DefaultCacheTTL = 24 * time.Hour
+	// Age until broken results get checked again
+	DefaultBrokenCacheTTL = time.Hour
+)
+
+// Result of the last check of an url.
+type CacheEntry struct {
+	Status    UrlStatus `json:"status"`
+	CheckedAt time.Time `json:"checked_at"`
+	// Validators of the response, sent with conditional requests once the entry is stale
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+// Age of entry at now.
+func (e CacheEntry) age(now time.Time) time.Duration {
+	return now.Sub(e.CheckedAt)
+}
+
+// Checks if a conditional request can revalidate the entry.
+func (e CacheEntry) hasValidators() bool {
+	return e.ETag != "" || e.LastModified != ""
+}
+
+// On disk cache of check results by normalized url, shared by all checks of a run.
+type ResultCache struct {
+	path string
+	// Time reachable and broken results are reused without request
+	ttl       time.Duration
+	brokenTTL time.Duration
+
+	mu      sync.Mutex
+	entries map[string]CacheEntry
+	// Number of results reused without request and revalidated with a conditional request
+	hits        int
+	revalidated int
+}
+
+// Reads cache file at path, a missing file starts an empty cache.
+func LoadResultCache(path string, ttl, brokenTTL time.Duration) (*ResultCache, error) {
+	c := &ResultCache{path: path, ttl: ttl, brokenTTL: brokenTTL, entries: map[string]CacheEntry{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read cache file: %w", err)
+	}
+	if err := json.Unmarshal(data, &c.entries); err != nil {
+		return nil, fmt.Errorf("invalid cache file %s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Writes all entries to the cache file.
+func (c *ResultCache) Save() error {
+	c.mu.Lock()
+	data, err := json.Marshal(c.entries)
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, data, 0o644)
+}
+
+// Number of reused and revalidated results.
+func (c *ResultCache) Stats() (hits, revalidated int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.revalidated
+}
+
+// Cached entry of url, fresh is set if it is younger than the ttl of its result.
+func (c *ResultCache) lookup(url string, now time.Time) (entry CacheEntry, found, fresh bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, found = c.entries[normalizeUrl(url)]
+	if !found {
+		return CacheEntry{}, false, false
+	}
+	ttl := c.ttl
+	if !entry.Status.IsReachable {
+		ttl = c.brokenTTL
+	}
+	fresh = entry.age(now) < ttl
+	if fresh {
+		c.hits += 1
+	}
+	return entry, true, fresh
+}
+
+// Stores result of a check, details of the current run are not cached.
+func (c *ResultCache) store(s UrlStatus, now time.Time) {
+	entry := CacheEntry{CheckedAt: now, ETag: s.etag, LastModified: s.lastModified}
+	s.Sources, s.NumOccured, s.LocalPath = nil, 0, ""
+	s.Rules, s.Ignored, s.IgnoreReason = nil, false, ""
+	s.notModified, s.fromCache, s.etag, s.lastModified = false, false, "", ""
+	entry.Status = s
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[normalizeUrl(s.Url)] = entry
+}
+
+// Counts a result that was confirmed by a not modified response.
+func (c *ResultCache) countRevalidated() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.revalidated += 1
+}
+
+// Cached status of entry with the details of inputUrl.
+func (e CacheEntry) statusFor(inputUrl ExtractedUrl) UrlStatus {
+	s := e.Status
+	s.Url = inputUrl.Url
+	s.NumOccured = inputUrl.NumOccured
+	s.Sources = inputUrl.Sources
+	s.etag, s.lastModified = e.ETag, e.LastModified
+	s.fromCache = true
+	return s
+}
+
+// Cache used by all checks, nil if results are not cached
+var resultCache *ResultCache
+
+// Overwrites module wide cache of check results, nil disables caching.
+func SetResultCache(c *ResultCache) {
+	resultCache = c
+}
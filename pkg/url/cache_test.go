@@ -0,0 +1,75 @@
+package url
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestResultCache(t *testing.T) {
+	t.Cleanup(func() { SetResultCache(nil) })
+	requests := map[string]int{}
+	conditional := 0
+	fakeServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path] += 1
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			conditional += 1
+			w.WriteHeader(http.StatusNotModified)
+		}
+	}))
+	defer fakeServer.Close()
+	path := filepath.Join(t.TempDir(), "cache.json")
+
+	t.Run("fresh results are reused", func(t *testing.T) {
+		cache, _ := LoadResultCache(path, time.Hour, 0)
+		SetResultCache(cache)
+		first := UrlIsAvailable(ExtractedUrl{Url: fakeServer.URL + "/page", NumOccured: 1})
+		second := UrlIsAvailable(ExtractedUrl{Url: fakeServer.URL + "/page", NumOccured: 3})
+		if !first.IsReachable || !second.IsReachable || second.NumOccured != 3 {
+			t.Errorf("got %v and %v", first, second)
+		}
+		if requests["/page"] != 1 {
+			t.Errorf("got %d requests expected 1", requests["/page"])
+		}
+		UrlIsAvailable(ExtractedUrl{Url: fakeServer.URL + "/broken"})
+		UrlIsAvailable(ExtractedUrl{Url: fakeServer.URL + "/broken"})
+		if requests["/broken"] != 2 {
+			t.Errorf("expected broken results to be checked again after broken ttl, got %d requests", requests["/broken"])
+		}
+		if hits, _ := cache.Stats(); hits != 1 {
+			t.Errorf("got %d hits expected 1", hits)
+		}
+		if err := cache.Save(); err != nil {
+			t.Fatalf("did not expect an error, got %v", err)
+		}
+	})
+	t.Run("stale results are revalidated", func(t *testing.T) {
+		cache, err := LoadResultCache(path, 0, 0)
+		if err != nil {
+			t.Fatalf("did not expect an error, got %v", err)
+		}
+		SetResultCache(cache)
+		got := UrlIsAvailable(ExtractedUrl{Url: fakeServer.URL + "/page"})
+		if !got.IsReachable || got.StatusCode != http.StatusOK {
+			t.Errorf("expected cached reachable status, got %v", got)
+		}
+		if _, revalidated := cache.Stats(); revalidated != 1 || conditional != 1 {
+			t.Errorf("got %d revalidated and %d conditional requests expected 1", revalidated, conditional)
+		}
+	})
+	t.Run("invalid cache file", func(t *testing.T) {
+		invalid := filepath.Join(t.TempDir(), "invalid.json")
+		os.WriteFile(invalid, []byte("[1, 2]"), 0o644)
+		if _, err := LoadResultCache(invalid, time.Hour, time.Hour); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
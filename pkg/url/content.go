@@ -0,0 +1,251 @@
+package url
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Kinds of documents links can be extracted from
+const (
+	DocumentHtml = "html"
+	DocumentFeed = "feed"
+	DocumentXml  = "xml"
+	DocumentJson = "json"
+	DocumentPdf  = "pdf"
+)
+
+// All kinds of documents links can be extracted from
+var DocumentKinds = []string{DocumentHtml, DocumentFeed, DocumentXml, DocumentJson, DocumentPdf}
+
+var (
+	// Uri actions in pdf annotations with literal or hex string: /URI (https://...) or /URI <68747470...>
+	pdfUriRegex = regexp.MustCompile(`/URI\s*(?:\(((?:\\.|[^\\)])*)\)|<([0-9a-fA-F\s]*)>)`)
+	// Compressed pdf streams, which can contain annotations in object streams
+	pdfFlateStreamRegex = regexp.MustCompile(`(?s)/FlateDecode.*?>>\s*stream\r?\n(.*?)endstream`)
+)
+
+// Maps a mime type from a Content-Type header to a document kind. Returns empty string for unknown types.
+func DocumentKindOf(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	switch {
+	case mediaType == "text/html" || mediaType == "application/xhtml+xml":
+		return DocumentHtml
+	case mediaType == "application/rss+xml" || mediaType == "application/atom+xml":
+		return DocumentFeed
+	case mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml"):
+		return DocumentXml
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return DocumentJson
+	case mediaType == "application/pdf":
+		return DocumentPdf
+	}
+	return ""
+}
+
+// Extracts unique http(s) urls from a document of the given kind and marks the page they were found on.
+// Relative links are resolved against the source page.
+func ExtractUrlsFromDocument(kind string, body []byte, sourcePage string) ([]ExtractedUrl, error) {
+	var links []string
+	var err error
+	switch kind {
+	case DocumentHtml:
+		return ExtractHttpUrlsFromPage(string(body), sourcePage), nil
+	case DocumentFeed, DocumentXml:
+		links, err = ExtractXmlLinks(body)
+	case DocumentJson:
+		links, err = ExtractJsonLinks(body)
+	case DocumentPdf:
+		links = ExtractPdfLinks(body)
+	default:
+		return nil, errors.New("unknown document kind " + kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	baseUrl, err := url.Parse(sourcePage)
+	if err != nil {
+		return nil, err
+	}
+	resolvedLinks := []string{}
+	for _, link := range links {
+		linkUrl, err := url.Parse(strings.TrimSpace(link))
+		if err != nil {
+			continue
+		}
+		resolvedLinks = append(resolvedLinks, baseUrl.ResolveReference(linkUrl).String())
+	}
+
+	extractedUrls := filterNoneHttpUrls(resolvedLinks)
+	for i := range extractedUrls {
+		extractedUrls[i].Sources = []UrlSource{{Page: sourcePage}}
+	}
+	return extractedUrls, nil
+}
+
+// Fetches all reachable documents of the report with one of the given kinds and extracts their links.
+// Urls that are already part of the report are skipped. Documents not allowed by crawlFilter are not fetched,
+// crawlFilter can be nil to fetch all documents. Warnings about documents that could not be read go to infoOutput.
+func ExtractUrlsFromLinkedDocuments(report UrlReport, kinds []string, crawlFilter *FilterStats, infoOutput io.Writer) []ExtractedUrl {
+	checkedUrls := make(map[string]bool)
+	for _, s := range report.UrlStatus {
+		checkedUrls[s.Url] = true
+	}
+
+	extractedUrls := []ExtractedUrl{}
+	for _, s := range report.UrlStatus {
+		if !s.IsReachable || s.LocalPath != "" || !slices.Contains(kinds, DocumentKindOf(s.ContentType)) {
+			continue
+		}
+		if crawlFilter != nil && !crawlFilter.Allows(s.Url) {
+			continue
+		}
+		body, contentType, err := GetDocumentFromUrl(s.Url)
+		if err != nil {
+			fmt.Fprintf(infoOutput, "%v\nWARNING: Failure to extract links from %s\n", err, s.Url)
+			continue
+		}
+		documentUrls, err := ExtractUrlsFromDocument(DocumentKindOf(contentType), body, s.Url)
+		if err != nil {
+			fmt.Fprintf(infoOutput, "%v\nWARNING: Failure to extract links from %s\n", err, s.Url)
+			continue
+		}
+		for _, e := range documentUrls {
+			if !checkedUrls[e.Url] {
+				extractedUrls = append(extractedUrls, e)
+			}
+		}
+	}
+	return MergeExtractedUrls(extractedUrls)
+}
+
+// Extracts links from xml documents: href attributes of any element, url attributes of enclosures
+// and media content, and the text of link and loc elements as used in RSS feeds and sitemaps.
+func ExtractXmlLinks(body []byte) ([]string, error) {
+	links := []string{}
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	decoder.Strict = false
+	textElement := false
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return links, nil
+		}
+		if err != nil {
+			return links, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			for _, attr := range t.Attr {
+				if attr.Name.Local == "href" || (attr.Name.Local == "url" && isXmlMediaElement(t.Name.Local)) {
+					links = append(links, attr.Value)
+				}
+			}
+			textElement = t.Name.Local == "link" || t.Name.Local == "loc"
+		case xml.CharData:
+			if textElement && len(bytes.TrimSpace(t)) > 0 {
+				links = append(links, string(bytes.TrimSpace(t)))
+			}
+		case xml.EndElement:
+			textElement = false
+		}
+	}
+}
+
+// Checks if xml element references media by an url attribute.
+func isXmlMediaElement(name string) bool {
+	return name == "enclosure" || name == "content" || name == "thumbnail"
+}
+
+// Extracts all string values from a json document that look like http(s) urls.
+func ExtractJsonLinks(body []byte) ([]string, error) {
+	var document any
+	if err := json.Unmarshal(body, &document); err != nil {
+		return nil, err
+	}
+	return collectJsonUrls(document, []string{}), nil
+}
+
+// Walks json values recursively and collects strings that are http(s) urls.
+func collectJsonUrls(value any, links []string) []string {
+	switch v := value.(type) {
+	case string:
+		if (strings.HasPrefix(v, prefixHttp) || strings.HasPrefix(v, prefixHttps)) && IsUrlValid(v) {
+			links = append(links, v)
+		}
+	case []any:
+		for _, element := range v {
+			links = collectJsonUrls(element, links)
+		}
+	case map[string]any:
+		for _, element := range v {
+			links = collectJsonUrls(element, links)
+		}
+	}
+	return links
+}
+
+// Extracts uri actions of link annotations from a pdf, including annotations in compressed streams.
+func ExtractPdfLinks(body []byte) []string {
+	links := findPdfUris(body)
+	for _, match := range pdfFlateStreamRegex.FindAllSubmatch(body, -1) {
+		reader, err := zlib.NewReader(bytes.NewReader(match[1]))
+		if err != nil {
+			continue
+		}
+		// streams can be cut off, use what could be decompressed
+		decompressed, _ := io.ReadAll(reader)
+		reader.Close()
+		links = append(links, findPdfUris(decompressed)...)
+	}
+	return links
+}
+
+// Finds uri actions in uncompressed pdf content.
+func findPdfUris(content []byte) []string {
+	links := []string{}
+	for _, match := range pdfUriRegex.FindAllSubmatch(content, -1) {
+		if match[1] != nil {
+			links = append(links, unescapePdfString(string(match[1])))
+			continue
+		}
+		hexString := strings.Join(strings.Fields(string(match[2])), "")
+		decoded := []byte{}
+		for i := 0; i+1 < len(hexString); i += 2 {
+			b, err := strconv.ParseUint(hexString[i:i+2], 16, 8)
+			if err != nil {
+				break
+			}
+			decoded = append(decoded, byte(b))
+		}
+		links = append(links, string(decoded))
+	}
+	return links
+}
+
+// Removes escaping from a pdf literal string.
+func unescapePdfString(s string) string {
+	var builder strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		builder.WriteByte(s[i])
+	}
+	return builder.String()
+}
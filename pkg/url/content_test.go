@@ -0,0 +1,160 @@
+package url
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDocumentKindOf(t *testing.T) {
+	cases := []struct {
+		contentType string
+		want        string
+	}{
+		{"text/html; charset=utf-8", DocumentHtml},
+		{"application/rss+xml", DocumentFeed},
+		{"application/atom+xml; charset=utf-8", DocumentFeed},
+		{"application/xml", DocumentXml},
+		{"image/svg+xml", DocumentXml},
+		{"application/json", DocumentJson},
+		{"application/ld+json", DocumentJson},
+		{"application/pdf", DocumentPdf},
+		{"image/png", ""},
+		{"", ""},
+	}
+	for _, tt := range cases {
+		t.Run(tt.contentType, func(t *testing.T) {
+			if got := DocumentKindOf(tt.contentType); got != tt.want {
+				t.Errorf("got %q want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractXmlLinks(t *testing.T) {
+	t.Run("rss items and enclosures", func(t *testing.T) {
+		body := `<?xml version="1.0"?><rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom"><channel>
+<link>https://example.com</link><atom:link href="https://example.com/feed.xml" rel="self"/>
+<item><title>Episode</title><link> https://example.com/episode-1 </link>
+<enclosure url="https://cdn.example.com/episode-1.mp3" length="1" type="audio/mpeg"/></item>
+</channel></rss>`
+		got, err := ExtractXmlLinks([]byte(body))
+		want := []string{"https://example.com", "https://example.com/feed.xml", "https://example.com/episode-1", "https://cdn.example.com/episode-1.mp3"}
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v want %v", got, want)
+		}
+	})
+
+	t.Run("atom entries", func(t *testing.T) {
+		body := `<feed xmlns="http://www.w3.org/2005/Atom"><link href="https://example.com/"/>
+<entry><link rel="alternate" href="/posts/1"/></entry></feed>`
+		got, err := ExtractXmlLinks([]byte(body))
+		want := []string{"https://example.com/", "/posts/1"}
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v want %v", got, want)
+		}
+	})
+
+	t.Run("generic xml and sitemaps", func(t *testing.T) {
+		body := `<root xmlns:xlink="http://www.w3.org/1999/xlink"><ref xlink:href="https://example.com/a"/>
+<url><loc>https://example.com/b</loc></url><name>https://example.com/not-a-link</name></root>`
+		got, err := ExtractXmlLinks([]byte(body))
+		want := []string{"https://example.com/a", "https://example.com/b"}
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v want %v", got, want)
+		}
+	})
+}
+
+func TestExtractJsonLinks(t *testing.T) {
+	body := `{"homepage": "https://example.com", "items": [{"url": "http://example.com/a", "name": "http"}, 42, null], "relative": "/b"}`
+	got, err := ExtractJsonLinks([]byte(body))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	slices.Sort(got)
+	want := []string{"http://example.com/a", "https://example.com"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+
+	_, err = ExtractJsonLinks([]byte(`{"broken":`))
+	if err == nil {
+		t.Errorf("expected an error for invalid json")
+	}
+}
+
+func TestExtractPdfLinks(t *testing.T) {
+	var compressed bytes.Buffer
+	w := zlib.NewWriter(&compressed)
+	w.Write([]byte(strings.Repeat("<< /Type /Annot /Subtype /Link >>\n", 50) + "<< /A << /S /URI /URI (https://example.com/compressed) >> >>"))
+	w.Close()
+
+	pdf := []byte("%PDF-1.5\n1 0 obj << /Type /Annot /Subtype /Link /A << /S /URI /URI (https://example.com/a\\(1\\)) >> >> endobj\n" +
+		"2 0 obj << /A << /URI <68747470733A2F2F6578616D706C652E636F6D2F686578> >> >> endobj\n" +
+		"3 0 obj << /Type /ObjStm /Filter /FlateDecode /Length 10 >>\nstream\n" + compressed.String() + "\nendstream\nendobj\n")
+	got := ExtractPdfLinks(pdf)
+	want := []string{"https://example.com/a(1)", "https://example.com/hex", "https://example.com/compressed"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+func TestExtractUrlsFromDocument(t *testing.T) {
+	t.Run("relative links get resolved against source page", func(t *testing.T) {
+		body := []byte(`<feed><link href="/posts/1"/><link href="mailto:me@example.com"/><link href="https://EXAMPLE.com/posts/1/"/></feed>`)
+		got, err := ExtractUrlsFromDocument(DocumentFeed, body, "https://example.com/feed.xml")
+		want := []ExtractedUrl{{Url: "https://example.com/posts/1", NumOccured: 2, Sources: []UrlSource{{Page: "https://example.com/feed.xml"}}}}
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v want %v", got, want)
+		}
+	})
+
+	t.Run("unknown kind fails", func(t *testing.T) {
+		_, err := ExtractUrlsFromDocument("doc", []byte{}, "https://example.com")
+		if err == nil {
+			t.Errorf("expected an error")
+		}
+	})
+}
+
+func TestExtractUrlsFromLinkedDocuments(t *testing.T) {
+	fakeServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api.json":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"self": "http://` + r.Host + `/api.json", "next": "http://` + r.Host + `/next"}`))
+		default:
+			w.Header().Set("Content-Type", "text/html")
+		}
+	}))
+	defer fakeServer.Close()
+
+	report := CreateUrlReport([]ExtractedUrl{
+		{Url: fakeServer.URL + "/api.json", NumOccured: 1},
+		{Url: fakeServer.URL + "/index.html", NumOccured: 1},
+	})
+	got := ExtractUrlsFromLinkedDocuments(report, []string{DocumentJson}, nil, io.Discard)
+	want := []ExtractedUrl{{Url: fakeServer.URL + "/next", NumOccured: 1, Sources: []UrlSource{{Page: fakeServer.URL + "/api.json"}}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
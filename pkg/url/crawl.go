@@ -0,0 +1,389 @@
+package url
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Page to fetch during a crawl, with its number of links away from a start page.
+type CrawlPage struct {
+	Url   string `json:"url"`
+	Depth int    `json:"depth"`
+}
+
+// Returned by Crawl and Resume together with the partial report, if the crawl was stopped
+var ErrCrawlStopped = errors.New("crawl stopped")
+
+// Progress of a crawl, saved to a state file to resume the crawl.
+type CrawlState struct {
+	StartUrls []string `json:"start_urls"`
+	// Limits of the crawl, a resumed crawl has to use the same
+	MaxDepth int `json:"max_depth"`
+	MaxPages int `json:"max_pages"`
+	// Pages to fetch at Depth
+	Depth   int         `json:"depth"`
+	Pending []CrawlPage `json:"pending,omitempty"`
+	// Urls of fetched pages that are not checked yet, and pages queued for the next depth
+	Unchecked []ExtractedUrl `json:"unchecked,omitempty"`
+	Next      []CrawlPage    `json:"next,omitempty"`
+	// Normalized urls of queued pages and of checked or filtered urls
+	Visited        []string    `json:"visited"`
+	Checked        []string    `json:"checked"`
+	RequestedPages int         `json:"requested_pages"`
+	FetchedPages   int         `json:"fetched_pages"`
+	UrlStatus      []UrlStatus `json:"url_status"`
+}
+
+// Reads state file of a crawl.
+func ReadCrawlState(path string) (CrawlState, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return CrawlState{}, fmt.Errorf("could not read crawl state: %w", err)
+	}
+	var state CrawlState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return CrawlState{}, fmt.Errorf("invalid crawl state %s: %w", path, err)
+	}
+	if len(state.StartUrls) == 0 {
+		return CrawlState{}, fmt.Errorf("invalid crawl state %s: no start urls", path)
+	}
+	return state, nil
+}
+
+// Checks that a crawl with max depth and max pages can resume from the state.
+func (s CrawlState) CheckLimits(maxDepth, maxPages int) error {
+	if s.MaxDepth != maxDepth || s.MaxPages != maxPages {
+		return fmt.Errorf("crawl state was saved with max depth %d and max pages %d, resume with the same limits", s.MaxDepth, s.MaxPages)
+	}
+	return nil
+}
+
+// Writes state to path, replaces the file only after it is written completely.
+func (s CrawlState) save(path string) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path+".tmp", data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// Recursive check of all pages on the hosts of the start pages.
+type Crawler struct {
+	// Max number of links between start page and crawled page, 0 crawls without limit
+	MaxDepth int
+	// Max number of fetched pages, 0 crawls without limit
+	MaxPages int
+	// Max number of parallel requests
+	MaxParallelRequests int
+	// Kinds of documents besides html whose links get extracted
+	DescendInto []string
+	// Filter of checked links and of crawled pages, nil allows everything
+	LinkFilter  *FilterStats
+	CrawlFilter *FilterStats
+	// Progress and warnings, os.Stderr if nil
+	InfoOutput io.Writer
+	// State is saved to StatePath after every batch of fetched pages or checked urls if set,
+	// the file is removed once the crawl is done
+	StatePath string
+	// Closing Stop ends the crawl after the current batch
+	Stop <-chan struct{}
+
+	state   CrawlState
+	hosts   map[string]bool
+	visited map[string]bool
+	checked map[string]bool
+}
+
+// Result of fetching a single page.
+type crawledPage struct {
+	page CrawlPage
+	urls []ExtractedUrl
+	err  error
+}
+
+// Crawls all pages reachable from the start pages on their hosts, level by level, and checks every found url.
+// Fails only if no start page could be fetched.
+func (c *Crawler) Crawl(startUrls []string) (UrlReport, error) {
+	state := CrawlState{StartUrls: startUrls, MaxDepth: c.MaxDepth, MaxPages: c.MaxPages}
+	for _, u := range startUrls {
+		state.Visited = append(state.Visited, normalizeUrl(u))
+		state.Pending = append(state.Pending, CrawlPage{Url: u})
+	}
+	return c.Resume(state)
+}
+
+// Continues crawl from state, fails if the state was saved with other limits. If the crawl is stopped,
+// the report of all urls checked so far is returned with ErrCrawlStopped.
+func (c *Crawler) Resume(state CrawlState) (UrlReport, error) {
+	if err := state.CheckLimits(c.MaxDepth, c.MaxPages); err != nil {
+		return UrlReport{}, err
+	}
+	start := time.Now()
+	c.state = state
+	c.hosts = map[string]bool{}
+	for _, u := range state.StartUrls {
+		c.hosts[crawlHostOf(u)] = true
+	}
+	c.visited = setOf(state.Visited)
+	c.checked = setOf(state.Checked)
+
+	for len(c.state.Pending) > 0 || len(c.state.Unchecked) > 0 {
+		if c.stopped() {
+			report := c.report(start)
+			report.AddMetaData("crawl_stopped", fmt.Sprintf("%d pages and %d urls pending", len(c.state.Pending)+len(c.state.Next), len(c.state.Unchecked)))
+			return report, ErrCrawlStopped
+		}
+		if len(c.state.Pending) > 0 {
+			if err := c.fetchBatch(); err != nil {
+				return UrlReport{}, err
+			}
+		} else {
+			c.checkBatch()
+		}
+		if err := c.saveState(); err != nil {
+			return UrlReport{}, fmt.Errorf("could not save crawl state: %w", err)
+		}
+	}
+	if c.StatePath != "" {
+		os.Remove(c.StatePath)
+	}
+	return c.report(start), nil
+}
+
+// Fetches the next pending pages and queues their urls that were not checked yet. Urls are checked
+// once all pages of the level are fetched.
+func (c *Crawler) fetchBatch() error {
+	if c.MaxPages > 0 && c.state.RequestedPages+len(c.state.Pending) > c.MaxPages {
+		c.state.Pending = c.state.Pending[:max(c.MaxPages-c.state.RequestedPages, 0)]
+	}
+	batch := c.state.Pending[:min(len(c.state.Pending), c.batchSize())]
+	c.state.RequestedPages += len(batch)
+	if len(batch) > 0 {
+		fmt.Fprintf(c.output(), "Crawling %d of %d pages at depth %d\n", len(batch), len(c.state.Pending), c.state.Depth)
+	}
+	extractedUrls := []ExtractedUrl{}
+	for _, result := range c.fetchPages(batch) {
+		if result.err != nil {
+			fmt.Fprintf(c.output(), "%v\nWARNING: Failure to extract links from %s\n", result.err, result.page.Url)
+			continue
+		}
+		c.state.FetchedPages += 1
+		extractedUrls = append(extractedUrls, result.urls...)
+	}
+	c.state.Pending = c.state.Pending[len(batch):]
+	if c.state.Depth == 0 && len(c.state.Pending) == 0 && c.state.FetchedPages == 0 {
+		return fmt.Errorf("none of %d start pages could be fetched", len(c.state.StartUrls))
+	}
+
+	merged := MergeExtractedUrls(extractedUrls)
+	// urls queued by earlier batches of the level get the sources of this batch
+	queued := map[string]bool{}
+	for _, e := range c.state.Unchecked {
+		queued[e.Url] = true
+	}
+	for _, e := range merged {
+		if queued[e.Url] {
+			c.state.Unchecked = append(c.state.Unchecked, e)
+		}
+	}
+	c.state.Unchecked = MergeExtractedUrls(append(c.state.Unchecked, c.uncheckedUrls(merged)...))
+	if len(c.state.Pending) == 0 && len(c.state.Unchecked) == 0 {
+		c.nextLevel()
+	}
+	return nil
+}
+
+// Checks the next unchecked urls and queues crawlable pages for the next level.
+func (c *Crawler) checkBatch() {
+	batch := c.state.Unchecked[:min(len(c.state.Unchecked), c.batchSize())]
+	report := CustomizableCreateUrlReport(batch, c.MaxParallelRequests)
+	c.state.UrlStatus = append(c.state.UrlStatus, report.UrlStatus...)
+	c.state.Unchecked = c.state.Unchecked[len(batch):]
+
+	if c.MaxDepth <= 0 || c.state.Depth+1 <= c.MaxDepth {
+		for _, s := range report.UrlStatus {
+			if c.isCrawlable(s) {
+				c.visited[s.Url] = true
+				c.state.Next = append(c.state.Next, CrawlPage{Url: s.Url, Depth: c.state.Depth + 1})
+			}
+		}
+	}
+	if len(c.state.Unchecked) == 0 {
+		c.nextLevel()
+	}
+}
+
+// Number of pages fetched or urls checked between saving the state.
+func (c *Crawler) batchSize() int {
+	return 4 * max(c.MaxParallelRequests, 1)
+}
+
+// Moves pages queued for the next level to the pending pages, no pages are queued once MaxPages pages were requested.
+func (c *Crawler) nextLevel() {
+	c.state.Pending, c.state.Next = c.state.Next, nil
+	if c.MaxPages > 0 && c.state.RequestedPages >= c.MaxPages {
+		c.state.Pending = nil
+	}
+	c.state.Depth += 1
+}
+
+// Saves state of the crawl to StatePath, if set.
+func (c *Crawler) saveState() error {
+	if c.StatePath == "" {
+		return nil
+	}
+	c.state.Visited = sortedKeys(c.visited)
+	c.state.Checked = sortedKeys(c.checked)
+	return c.state.save(c.StatePath)
+}
+
+// Checks if Stop is closed.
+func (c *Crawler) stopped() bool {
+	select {
+	case <-c.Stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// Report of all urls checked so far.
+func (c *Crawler) report(start time.Time) UrlReport {
+	report := NewUrlReport(start, time.Since(start), c.state.UrlStatus)
+	report.AddMetaData("crawled_pages", fmt.Sprint(c.state.FetchedPages))
+	return report
+}
+
+// Set of values.
+func setOf(values []string) map[string]bool {
+	set := map[string]bool{}
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
+
+// Sorted keys of set.
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// Fetches pages in parallel and extracts their links, results keep the order of pages.
+func (c *Crawler) fetchPages(pages []CrawlPage) []crawledPage {
+	results := make([]crawledPage, len(pages))
+	inputChan := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < max(c.MaxParallelRequests, 1); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range inputChan {
+				results[index] = c.fetchPage(pages[index])
+			}
+		}()
+	}
+	for i := range pages {
+		inputChan <- i
+	}
+	close(inputChan)
+	wg.Wait()
+	return results
+}
+
+// Fetches page and extracts its links with the extraction of its document kind.
+func (c *Crawler) fetchPage(page CrawlPage) crawledPage {
+	body, contentType, err := GetDocumentFromUrl(page.Url)
+	if err != nil {
+		return crawledPage{page: page, err: err}
+	}
+	kind := DocumentKindOf(contentType)
+	if kind == "" || kind == DocumentHtml {
+		return crawledPage{page: page, urls: ExtractUrlsFromHtmlPage(string(body), page.Url)}
+	}
+	urls, err := ExtractUrlsFromDocument(kind, body, page.Url)
+	return crawledPage{page: page, urls: urls, err: err}
+}
+
+// Extracts unique http(s) urls from html page like ExtractHttpUrlsFromPage, and additionally
+// relative href and src links resolved against the page.
+func ExtractUrlsFromHtmlPage(body, sourcePage string) []ExtractedUrl {
+	links := findStrictUrls(body)
+	if pageUrl, err := url.Parse(sourcePage); err == nil {
+		for _, link := range ExtractHtmlLinks(body) {
+			linkUrl, err := url.Parse(link.Link)
+			// absolute links are already found in the text
+			if err != nil || linkUrl.IsAbs() {
+				continue
+			}
+			links = append(links, pageUrl.ResolveReference(linkUrl).String())
+		}
+	}
+	extractedUrls := filterNoneHttpUrls(links)
+	for i := range extractedUrls {
+		extractedUrls[i].Sources = []UrlSource{{Page: sourcePage}}
+	}
+	return extractedUrls
+}
+
+// Urls that were not checked before and are allowed by the link filter, marks them as checked.
+func (c *Crawler) uncheckedUrls(urls []ExtractedUrl) []ExtractedUrl {
+	unchecked := []ExtractedUrl{}
+	for _, e := range urls {
+		if c.checked[e.Url] {
+			continue
+		}
+		// filtered urls are marked as well, so the filter counts them once
+		c.checked[e.Url] = true
+		if c.LinkFilter != nil && !c.LinkFilter.Allows(e.Url) {
+			continue
+		}
+		unchecked = append(unchecked, e)
+	}
+	return unchecked
+}
+
+// Checks if links of a checked url get extracted: reachable html or descend document on a crawled host,
+// not visited yet and allowed by the crawl filter.
+func (c *Crawler) isCrawlable(s UrlStatus) bool {
+	kind := DocumentKindOf(s.ContentType)
+	if !s.IsReachable || c.visited[s.Url] || !c.hosts[crawlHostOf(s.Url)] {
+		return false
+	}
+	if kind != DocumentHtml && !slices.Contains(c.DescendInto, kind) {
+		return false
+	}
+	return c.CrawlFilter == nil || c.CrawlFilter.Allows(s.Url)
+}
+
+// Host with port of url, pages of other ports are not crawled.
+func crawlHostOf(rawUrl string) string {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Host)
+}
+
+// Writer for progress and warnings.
+func (c *Crawler) output() io.Writer {
+	if c.InfoOutput == nil {
+		return os.Stderr
+	}
+	return c.InfoOutput
+}
@@ -0,0 +1,160 @@
+package url
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestCrawler(t *testing.T) {
+	external := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<a href="/not-crawled">`))
+	}))
+	defer external.Close()
+	pages := map[string]string{
+		"/":          `<a href="/a">a</a> <a href="/b">b</a> <a href="` + external.URL + `/">external</a>`,
+		"/a":         `<a href="/c">c</a> <a href="/missing">missing</a>`,
+		"/b":         `<a href="/">home</a> <a href="/private/d">d</a>`,
+		"/c":         `<a href="/deep">deep</a>`,
+		"/deep":      `no links`,
+		"/private/d": `<a href="/private/e">e</a>`,
+	}
+	// called for every fetched page
+	var onFetch func(path string)
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if onFetch != nil && r.Method == http.MethodGet {
+			onFetch(r.URL.Path)
+		}
+		page, ok := pages[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(page))
+	}))
+	defer site.Close()
+
+	checkedUrls := func(r UrlReport) []string {
+		urls := []string{}
+		for _, s := range r.UrlStatus {
+			urls = append(urls, s.Url)
+		}
+		slices.Sort(urls)
+		return urls
+	}
+
+	t.Run("all pages of the start host", func(t *testing.T) {
+		crawler := Crawler{MaxParallelRequests: 2, InfoOutput: io.Discard}
+		report, err := crawler.Crawl([]string{site.URL + "/"})
+		if err != nil {
+			t.Fatalf("did not expect an error, got %v", err)
+		}
+		want := []string{external.URL, site.URL, site.URL + "/a", site.URL + "/b", site.URL + "/c", site.URL + "/deep",
+			site.URL + "/missing", site.URL + "/private/d", site.URL + "/private/e"}
+		slices.Sort(want)
+		if got := checkedUrls(report); !slices.Equal(got, want) {
+			t.Errorf("got %v expected %v", got, want)
+		}
+		if report.MetaData["crawled_pages"] != "6" {
+			t.Errorf("got %s crawled pages expected 6", report.MetaData["crawled_pages"])
+		}
+	})
+	t.Run("max depth and crawl filter", func(t *testing.T) {
+		filter, _ := NewUrlFilter(nil, []string{"/private/"}, false)
+		stats := filter.NewStats()
+		crawler := Crawler{MaxDepth: 1, MaxParallelRequests: 2, CrawlFilter: &stats, InfoOutput: io.Discard}
+		report, err := crawler.Crawl([]string{site.URL + "/"})
+		if err != nil {
+			t.Fatalf("did not expect an error, got %v", err)
+		}
+		for _, u := range checkedUrls(report) {
+			if u == site.URL+"/deep" || u == site.URL+"/private/e" {
+				t.Errorf("did not expect %s to be checked", u)
+			}
+		}
+		if report.MetaData["crawled_pages"] != "3" {
+			t.Errorf("got %s crawled pages expected 3", report.MetaData["crawled_pages"])
+		}
+	})
+	t.Run("max pages", func(t *testing.T) {
+		crawler := Crawler{MaxPages: 2, MaxParallelRequests: 2, InfoOutput: io.Discard}
+		report, _ := crawler.Crawl([]string{site.URL + "/"})
+		if report.MetaData["crawled_pages"] != "2" {
+			t.Errorf("got %s crawled pages expected 2", report.MetaData["crawled_pages"])
+		}
+	})
+	t.Run("stop and resume", func(t *testing.T) {
+		statePath := filepath.Join(t.TempDir(), "state.json")
+		stop := make(chan struct{})
+		onFetch = func(path string) {
+			if path == "/c" {
+				close(stop)
+			}
+		}
+		t.Cleanup(func() { onFetch = nil })
+		crawler := Crawler{MaxParallelRequests: 1, InfoOutput: io.Discard, StatePath: statePath, Stop: stop}
+		partial, err := crawler.Crawl([]string{site.URL + "/"})
+		if !errors.Is(err, ErrCrawlStopped) {
+			t.Fatalf("expected crawl to be stopped, got %v", err)
+		}
+		if partial.MetaData["crawl_stopped"] == "" || len(partial.UrlStatus) == 0 {
+			t.Errorf("expected partial report, got %v", partial)
+		}
+
+		onFetch = nil
+		state, err := ReadCrawlState(statePath)
+		if err != nil {
+			t.Fatalf("did not expect an error, got %v", err)
+		}
+		resumed := Crawler{MaxParallelRequests: 1, InfoOutput: io.Discard, StatePath: statePath}
+		report, err := resumed.Resume(state)
+		if err != nil {
+			t.Fatalf("did not expect an error, got %v", err)
+		}
+		if got := checkedUrls(report); len(got) != 9 || report.MetaData["crawled_pages"] != "6" {
+			t.Errorf("got %v and %s crawled pages, expected 9 urls of 6 pages", got, report.MetaData["crawled_pages"])
+		}
+		if _, err := os.Stat(statePath); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("expected state file to be removed after the crawl, got %v", err)
+		}
+	})
+	t.Run("stop during a level", func(t *testing.T) {
+		statePath := filepath.Join(t.TempDir(), "state.json")
+		stop := make(chan struct{})
+		onFetch = func(path string) {
+			if path == "/a" {
+				close(stop)
+			}
+		}
+		t.Cleanup(func() { onFetch = nil })
+		crawler := Crawler{MaxParallelRequests: 1, MaxDepth: 2, InfoOutput: io.Discard, StatePath: statePath, Stop: stop}
+		start := []string{site.URL + "/", site.URL + "/a", site.URL + "/b", site.URL + "/c", site.URL + "/deep", site.URL + "/private/d"}
+		if _, err := crawler.Crawl(start); !errors.Is(err, ErrCrawlStopped) {
+			t.Fatalf("expected crawl to be stopped, got %v", err)
+		}
+		state, err := ReadCrawlState(statePath)
+		if err != nil {
+			t.Fatalf("did not expect an error, got %v", err)
+		}
+		if state.Depth != 0 || len(state.Pending) != 2 || state.FetchedPages != 4 || state.MaxDepth != 2 {
+			t.Errorf("expected state after the first batch of 4 pages with max depth 2, got %+v", state)
+		}
+		resumed := Crawler{MaxParallelRequests: 1, InfoOutput: io.Discard, StatePath: statePath}
+		if _, err := resumed.Resume(state); err == nil {
+			t.Error("expected an error for a resume with other limits")
+		}
+	})
+	t.Run("unreachable start page", func(t *testing.T) {
+		crawler := Crawler{MaxParallelRequests: 2, InfoOutput: io.Discard}
+		if _, err := crawler.Crawl([]string{site.URL + "/missing"}); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
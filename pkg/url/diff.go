@@ -0,0 +1,123 @@
+package url
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Changes of urls between a baseline report and a current report.
+type ReportDiff struct {
+	// Broken now, but reachable or not present in baseline
+	NewlyBroken []UrlStatus
+	// Broken in baseline, reachable now
+	Fixed []UrlStatus
+	// Broken in baseline and now
+	StillBroken []UrlStatus
+	// Reachable now, not present in baseline
+	Added []UrlStatus
+	// Present in baseline, not present now
+	Removed []UrlStatus
+}
+
+// Helper construct of ReportDiff to customize the JSON conversion
+type JsonReportDiff struct {
+	NewlyBroken []JsonUrlStatus `json:"newly_broken"`
+	Fixed       []JsonUrlStatus `json:"fixed"`
+	StillBroken []JsonUrlStatus `json:"still_broken"`
+	Added       []JsonUrlStatus `json:"added"`
+	Removed     []JsonUrlStatus `json:"removed"`
+}
+
+// Compares urls of current report with baseline report. Urls of baseline get matched by their url.
+func DiffReports(baseline, current UrlReport) ReportDiff {
+	diff := ReportDiff{
+		NewlyBroken: []UrlStatus{},
+		Fixed:       []UrlStatus{},
+		StillBroken: []UrlStatus{},
+		Added:       []UrlStatus{},
+		Removed:     []UrlStatus{},
+	}
+	baselineStatus := make(map[string]UrlStatus)
+	for _, s := range baseline.UrlStatus {
+		baselineStatus[s.Url] = s
+	}
+	currentUrls := make(map[string]bool)
+	for _, s := range current.UrlStatus {
+		currentUrls[s.Url] = true
+		before, found := baselineStatus[s.Url]
+		switch {
+		case s.Ignored, s.Skipped:
+		case s.IsBroken() && (!found || !before.IsBroken()):
+			diff.NewlyBroken = append(diff.NewlyBroken, s)
+		case s.IsBroken():
+			diff.StillBroken = append(diff.StillBroken, s)
+		case !found:
+			diff.Added = append(diff.Added, s)
+		case before.IsBroken():
+			diff.Fixed = append(diff.Fixed, s)
+		}
+	}
+	for _, s := range baseline.UrlStatus {
+		if !currentUrls[s.Url] {
+			diff.Removed = append(diff.Removed, s)
+		}
+	}
+	return diff
+}
+
+// Checks if any url broke since the baseline.
+func (d ReportDiff) HasNewlyBroken() bool {
+	return len(d.NewlyBroken) > 0
+}
+
+// Adds the number of urls in each class of the diff as MetaData to report.
+func (d ReportDiff) AddMetaDataTo(r UrlReport) {
+	r.AddMetaData("newly_broken_urls", fmt.Sprint(len(d.NewlyBroken)))
+	r.AddMetaData("fixed_urls", fmt.Sprint(len(d.Fixed)))
+	r.AddMetaData("still_broken_urls", fmt.Sprint(len(d.StillBroken)))
+	r.AddMetaData("added_urls", fmt.Sprint(len(d.Added)))
+	r.AddMetaData("removed_urls", fmt.Sprint(len(d.Removed)))
+}
+
+// String representation of ReportDiff with all changed urls.
+func (d ReportDiff) String() string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("Compared to baseline: newly broken: %d, fixed: %d, still broken: %d, added: %d, removed: %d\n",
+		len(d.NewlyBroken), len(d.Fixed), len(d.StillBroken), len(d.Added), len(d.Removed)))
+	classes := []struct {
+		name      string
+		urlStatus []UrlStatus
+	}{
+		{"Newly broken", d.NewlyBroken},
+		{"Fixed", d.Fixed},
+		{"Still broken", d.StillBroken},
+		{"Added", d.Added},
+		{"Removed", d.Removed},
+	}
+	for _, class := range classes {
+		if len(class.urlStatus) == 0 {
+			continue
+		}
+		builder.WriteString("\n" + class.name + ":\n")
+		for _, s := range class.urlStatus {
+			builder.WriteString(fmt.Sprintf("\t%s\t%s\n", s.Url, s.StatusMessage))
+		}
+	}
+	return builder.String()
+}
+
+// Converts ReportDiff to JSON string
+func (d ReportDiff) Json() (string, error) {
+	jsonBytes, err := json.Marshal(JsonReportDiff{
+		NewlyBroken: convertUrlStatusToJsonStuct(d.NewlyBroken),
+		Fixed:       convertUrlStatusToJsonStuct(d.Fixed),
+		StillBroken: convertUrlStatusToJsonStuct(d.StillBroken),
+		Added:       convertUrlStatusToJsonStuct(d.Added),
+		Removed:     convertUrlStatusToJsonStuct(d.Removed),
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(jsonBytes), nil
+}
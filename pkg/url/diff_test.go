@@ -0,0 +1,78 @@
+package url
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiffReports(t *testing.T) {
+	baseline := NewUrlReport(time.Now(), time.Second, []UrlStatus{
+		{Url: "https://a.de/stays-ok", IsReachable: true},
+		{Url: "https://a.de/breaks", IsReachable: true},
+		{Url: "https://a.de/gets-fixed", IsReachable: false},
+		{Url: "https://a.de/stays-broken", IsReachable: false},
+		{Url: "https://a.de/removed", IsReachable: false},
+	})
+	current := NewUrlReport(time.Now(), time.Second, []UrlStatus{
+		{Url: "https://a.de/stays-ok", IsReachable: true},
+		{Url: "https://a.de/breaks", IsReachable: false, StatusMessage: "Not Found"},
+		{Url: "https://a.de/gets-fixed", IsReachable: true},
+		{Url: "https://a.de/stays-broken", IsReachable: false},
+		{Url: "https://a.de/new-and-broken", IsReachable: false},
+		{Url: "https://a.de/new", IsReachable: true},
+	})
+	diff := DiffReports(baseline, current)
+
+	urlsOf := func(urlStatus []UrlStatus) string {
+		urls := []string{}
+		for _, s := range urlStatus {
+			urls = append(urls, s.Url)
+		}
+		return strings.Join(urls, " ")
+	}
+	cases := []struct {
+		name string
+		got  []UrlStatus
+		want string
+	}{
+		{"newly broken", diff.NewlyBroken, "https://a.de/breaks https://a.de/new-and-broken"},
+		{"fixed", diff.Fixed, "https://a.de/gets-fixed"},
+		{"still broken", diff.StillBroken, "https://a.de/stays-broken"},
+		{"added", diff.Added, "https://a.de/new"},
+		{"removed", diff.Removed, "https://a.de/removed"},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := urlsOf(tt.got); got != tt.want {
+				t.Errorf("got %q expected %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("newly broken urls fail", func(t *testing.T) {
+		if !diff.HasNewlyBroken() {
+			t.Error("expected newly broken urls")
+		}
+		if DiffReports(current, current).HasNewlyBroken() {
+			t.Error("expected no newly broken urls comparing a report with itself")
+		}
+	})
+	t.Run("meta data", func(t *testing.T) {
+		report := NewUrlReport(time.Now(), time.Second, []UrlStatus{})
+		diff.AddMetaDataTo(report)
+		if report.MetaData["newly_broken_urls"] != "2" || report.MetaData["removed_urls"] != "1" {
+			t.Errorf("got unexpected meta data %v", report.MetaData)
+		}
+	})
+	t.Run("text and json output", func(t *testing.T) {
+		if got := diff.String(); !strings.HasPrefix(got, "Compared to baseline: newly broken: 2, fixed: 1, still broken: 1, added: 1, removed: 1\n") ||
+			!strings.Contains(got, "Newly broken:\n\thttps://a.de/breaks\tNot Found\n") {
+			t.Errorf("got unexpected text output %q", got)
+		}
+		got, err := diff.Json()
+		if err != nil || !strings.Contains(got, `"fixed":[{"url":"https://a.de/gets-fixed"`) {
+			t.Errorf("got unexpected json output %q, %v", got, err)
+		}
+	})
+}
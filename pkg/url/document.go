@@ -0,0 +1,164 @@
+package url
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Link found in a document with the line it was found on.
+type DocumentLink struct {
+	Link string
+	Line int
+}
+
+// Extracts links from the content of a document.
+type linkExtractor func(content string) []DocumentLink
+
+// Link extractors per supported file extension
+var documentExtractors = map[string]linkExtractor{
+	".html":     ExtractHtmlLinks,
+	".htm":      ExtractHtmlLinks,
+	".md":       ExtractMarkdownLinks,
+	".markdown": ExtractMarkdownLinks,
+	".rst":      ExtractRstLinks,
+	".txt":      ExtractTextLinks,
+}
+
+var (
+	// Finds href and src attribute values in html content
+	htmlLinkAttributeRegex = regexp.MustCompile(`(?i)\s(?:href|src)\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+
+	// Markdown inline links and images: [text](url "title") and ![alt](<url>)
+	markdownInlineLinkRegex = regexp.MustCompile(`!?\[[^\]]*\]\(\s*(?:<([^>]*)>|([^)\s]+))(?:\s+(?:"[^"]*"|'[^']*'|\([^)]*\)))?\s*\)`)
+	// Markdown reference definitions: [id]: url "title"
+	markdownReferenceRegex = regexp.MustCompile(`^ {0,3}\[[^\]]+\]:\s*(?:<([^>]*)>|(\S+))`)
+	// Markdown autolinks: <https://example.com>
+	markdownAutolinkRegex = regexp.MustCompile(`<([a-zA-Z][a-zA-Z0-9+.-]*:[^<>\s]+)>`)
+	// Markdown inline code spans, links in them are not checked
+	markdownCodeSpanRegex = regexp.MustCompile("`[^`]*`")
+
+	// reStructuredText embedded links: `text <url>`_
+	rstEmbeddedLinkRegex = regexp.MustCompile("`[^`<]*<([^>]+)>`_{1,2}")
+	// reStructuredText link targets: .. _name: url
+	rstLinkTargetRegex = regexp.MustCompile(`^\s*\.\.\s+_[^:]+:\s+(\S+)`)
+	// reStructuredText directives with a file argument: .. image:: path
+	rstDirectiveRegex = regexp.MustCompile(`^\s*\.\.\s+(?:image|figure|include|literalinclude)::\s+(\S+)`)
+)
+
+// Checks if links of the file can be extracted.
+func isDocumentFile(p string) bool {
+	_, found := documentExtractors[strings.ToLower(filepath.Ext(p))]
+	return found
+}
+
+// Extracts links from content by the file extension of the document.
+func extractDocumentLinks(file, content string) []DocumentLink {
+	extractor, found := documentExtractors[strings.ToLower(filepath.Ext(file))]
+	if !found {
+		return ExtractTextLinks(content)
+	}
+	return extractor(content)
+}
+
+// Extracts all href and src attribute values from html content.
+func ExtractHtmlLinks(body string) []DocumentLink {
+	links := []DocumentLink{}
+	for _, match := range htmlLinkAttributeRegex.FindAllStringSubmatchIndex(body, -1) {
+		link := strings.TrimSpace(submatch(body, match, 1) + submatch(body, match, 2))
+		if link != "" {
+			links = append(links, DocumentLink{Link: link, Line: strings.Count(body[:match[0]], "\n") + 1})
+		}
+	}
+	return links
+}
+
+// Extracts inline links, images, reference definitions, autolinks and plain urls from markdown. Code blocks and code spans are skipped.
+func ExtractMarkdownLinks(content string) []DocumentLink {
+	links := []DocumentLink{}
+	inCodeFence := false
+	codeFence := ""
+	for i, line := range strings.Split(content, "\n") {
+		trimmedLine := strings.TrimSpace(line)
+		if inCodeFence {
+			if strings.HasPrefix(trimmedLine, codeFence) {
+				inCodeFence = false
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmedLine, "```") || strings.HasPrefix(trimmedLine, "~~~") {
+			inCodeFence = true
+			codeFence = trimmedLine[:3]
+			continue
+		}
+
+		line = markdownCodeSpanRegex.ReplaceAllStringFunc(line, blankOut)
+		lineLinks := []string{}
+		line, lineLinks = extractAndBlankOut(line, markdownInlineLinkRegex, lineLinks)
+		line, lineLinks = extractAndBlankOut(line, markdownReferenceRegex, lineLinks)
+		line, lineLinks = extractAndBlankOut(line, markdownAutolinkRegex, lineLinks)
+		lineLinks = append(lineLinks, findStrictUrls(line)...)
+		links = appendDocumentLinks(links, lineLinks, i+1)
+	}
+	return links
+}
+
+// Extracts embedded links, link targets, file directives and plain urls from reStructuredText.
+func ExtractRstLinks(content string) []DocumentLink {
+	links := []DocumentLink{}
+	for i, line := range strings.Split(content, "\n") {
+		lineLinks := []string{}
+		line, lineLinks = extractAndBlankOut(line, rstEmbeddedLinkRegex, lineLinks)
+		line, lineLinks = extractAndBlankOut(line, rstLinkTargetRegex, lineLinks)
+		line, lineLinks = extractAndBlankOut(line, rstDirectiveRegex, lineLinks)
+		lineLinks = append(lineLinks, findStrictUrls(line)...)
+		links = appendDocumentLinks(links, lineLinks, i+1)
+	}
+	return links
+}
+
+// Extracts plain urls from text.
+func ExtractTextLinks(content string) []DocumentLink {
+	links := []DocumentLink{}
+	for i, line := range strings.Split(content, "\n") {
+		links = appendDocumentLinks(links, findStrictUrls(line), i+1)
+	}
+	return links
+}
+
+// Adds the first non empty submatch of each regex match to links and replaces the match in line with spaces, so it is not found again.
+func extractAndBlankOut(line string, regex *regexp.Regexp, links []string) (string, []string) {
+	for _, match := range regex.FindAllStringSubmatchIndex(line, -1) {
+		for group := 1; group*2 < len(match); group++ {
+			if link := submatch(line, match, group); link != "" {
+				links = append(links, link)
+				break
+			}
+		}
+	}
+	return regex.ReplaceAllStringFunc(line, blankOut), links
+}
+
+// Returns the submatch of group from a match index, or empty string if the group did not match.
+func submatch(s string, match []int, group int) string {
+	if match[group*2] < 0 {
+		return ""
+	}
+	return s[match[group*2]:match[group*2+1]]
+}
+
+// Replaces all characters with spaces.
+func blankOut(s string) string {
+	return strings.Repeat(" ", len(s))
+}
+
+// Adds all links of a line as DocumentLink.
+func appendDocumentLinks(links []DocumentLink, lineLinks []string, line int) []DocumentLink {
+	for _, link := range lineLinks {
+		link = strings.TrimSpace(link)
+		if link != "" {
+			links = append(links, DocumentLink{Link: link, Line: line})
+		}
+	}
+	return links
+}
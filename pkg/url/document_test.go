@@ -0,0 +1,77 @@
+package url
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractHtmlLinks(t *testing.T) {
+	body := `<html><head><link href="/style.css"><script src='app.js'></script></head>
+<body><a href="https://www.google.de">google</a>
+<a HREF = "../about/">about</a><a href="">empty</a></body></html>`
+	got := ExtractHtmlLinks(body)
+	want := []DocumentLink{{"/style.css", 1}, {"app.js", 1}, {"https://www.google.de", 2}, {"../about/", 3}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+func TestExtractMarkdownLinks(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		want    []DocumentLink
+	}{
+		{
+			"inline link with title",
+			`See [setup](../guide/setup.md#install "Setup") for more.`,
+			[]DocumentLink{{"../guide/setup.md#install", 1}},
+		}, {
+			"image link",
+			"# Title\n![logo](<images/logo one.png>)",
+			[]DocumentLink{{"images/logo one.png", 2}},
+		}, {
+			"reference definition",
+			"[docs][1]\n\n[1]: https://www.google.de \"Google\"",
+			[]DocumentLink{{"https://www.google.de", 3}},
+		}, {
+			"autolink",
+			"Visit <https://www.heise.de> or <mailto:me@example.com>",
+			[]DocumentLink{{"https://www.heise.de", 1}, {"mailto:me@example.com", 1}},
+		}, {
+			"plain url is found once next to a link with the same url",
+			"[google](https://www.google.de) https://www.google.de",
+			[]DocumentLink{{"https://www.google.de", 1}, {"https://www.google.de", 1}},
+		}, {
+			"links in code are skipped",
+			"`https://www.google.de`\n```\n[a](b.md)\n```\n[c](d.md)",
+			[]DocumentLink{{"d.md", 5}},
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ExtractMarkdownLinks(tt.content)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractRstLinks(t *testing.T) {
+	content := "See `Google <https://www.google.de>`_ and https://www.heise.de\n\n.. _setup: guide/setup.rst\n.. image:: images/logo.png"
+	got := ExtractRstLinks(content)
+	want := []DocumentLink{{"https://www.google.de", 1}, {"https://www.heise.de", 1}, {"guide/setup.rst", 3}, {"images/logo.png", 4}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+func TestExtractTextLinks(t *testing.T) {
+	content := "first line\nhttps://www.google.de and http://www.heise.de/news\nsetup.md"
+	got := ExtractTextLinks(content)
+	want := []DocumentLink{{"https://www.google.de", 2}, {"http://www.heise.de/news", 2}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
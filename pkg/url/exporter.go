@@ -5,29 +5,72 @@ import (
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"strings"
 )
 
 var (
 	csvHeader = []string{
 		"url", "is_reachable", "status_message", "content_length", "response_time", "num_occured",
+		"failure_category", "ignored", "ignore_reason", "skipped",
 	}
 )
 
 // Helper construct of UrlReport to customize the JSON conversion
 type JsonUrlReport struct {
-	ExecutedAt string          `json:"executed_at"`
-	Runtime    string          `json:"runtime"`
-	UrlStatus  []JsonUrlStatus `json:"url_status"`
+	ExecutedAt string             `json:"executed_at"`
+	Runtime    string             `json:"runtime"`
+	MetaData   map[string]string  `json:"meta_data,omitempty"`
+	Summary    *JsonReportSummary `json:"summary,omitempty"`
+	UrlStatus  []JsonUrlStatus    `json:"url_status"`
+}
+
+// Helper construct of ReportSummary to customize the JSON conversion
+type JsonReportSummary struct {
+	ByStatusCode      map[int]int             `json:"by_status_code"`
+	ByFailureCategory map[FailureCategory]int `json:"by_failure_category"`
+	ByHost            []JsonHostSummary       `json:"by_host"`
+	Slowest           []JsonSlowUrl           `json:"slowest"`
+	ResponseTime      JsonResponseTimeSummary `json:"response_time"`
+}
+
+// Helper construct of HostSummary to customize the JSON conversion
+type JsonHostSummary struct {
+	Host   string `json:"host"`
+	Total  int    `json:"total"`
+	Broken int    `json:"broken"`
+}
+
+// One of the slowest urls of a report
+type JsonSlowUrl struct {
+	Url          string `json:"url"`
+	ResponseTime string `json:"response_time"`
+}
+
+// Helper construct of ResponseTimeSummary to customize the JSON conversion
+type JsonResponseTimeSummary struct {
+	Min    string `json:"min"`
+	Median string `json:"median"`
+	P95    string `json:"p95"`
+	Max    string `json:"max"`
 }
 
 // Helper construct of UrlStatus to customize the JSON conversion
 type JsonUrlStatus struct {
-	Url           string `json:"url"`
-	IsReachable   bool   `json:"is_reachable"`
-	StatusMessage string `json:"status_message"`
-	ContentLength int64  `json:"content_length"`
-	ResponseTime  string `json:"response_time"`
-	NumOccured    int    `json:"num_occured"`
+	Url           string      `json:"url"`
+	IsReachable   bool        `json:"is_reachable"`
+	StatusMessage string      `json:"status_message"`
+	ContentLength int64       `json:"content_length"`
+	ResponseTime  string      `json:"response_time"`
+	NumOccured    int         `json:"num_occured"`
+	Sources       []UrlSource `json:"sources,omitempty"`
+	Redirects     []string    `json:"redirects,omitempty"`
+	// Details for not reachable urls
+	StatusCode      int             `json:"status_code,omitempty"`
+	FailureCategory FailureCategory `json:"failure_category,omitempty"`
+	Ignored         bool            `json:"ignored,omitempty"`
+	IgnoreReason    string          `json:"ignore_reason,omitempty"`
+	Skipped         bool            `json:"skipped,omitempty"`
+	Rules           []string        `json:"rules,omitempty"`
 }
 
 // Converts UrlReport to JSON string
@@ -59,6 +102,10 @@ func (r *UrlReport) Csv(writeHeader bool) (string, error) {
 			fmt.Sprint(status.ContentLength),
 			status.ResponseTime.String(),
 			fmt.Sprint(status.NumOccured),
+			string(status.FailureCategory),
+			fmt.Sprint(status.Ignored),
+			status.IgnoreReason,
+			fmt.Sprint(status.Skipped),
 		}
 		w.Write(lineContent)
 	}
@@ -71,6 +118,20 @@ func (r *UrlReport) Csv(writeHeader bool) (string, error) {
 	return buf.String(), nil
 }
 
+// Converts UrlReport to Csv string, with all MetaData as leading comment lines "# key: value".
+func (r *UrlReport) CsvWithMetaData(writeHeader bool) (string, error) {
+	csvOutput, err := r.Csv(writeHeader)
+	if err != nil {
+		return "", err
+	}
+	var builder strings.Builder
+	for _, k := range r.sortedMetaDataKeys() {
+		builder.WriteString(fmt.Sprintf("# %s: %s\n", k, strings.ReplaceAll(r.MetaData[k], "\n", " ")))
+	}
+	builder.WriteString(csvOutput)
+	return builder.String(), nil
+}
+
 // Internal conversion, to set time.* values as we want them to be
 func convertToJsonStruct(report UrlReport) (JsonUrlReport, error) {
 	timeConverted, err := report.ExecutedAt.MarshalText()
@@ -81,21 +142,57 @@ func convertToJsonStruct(report UrlReport) (JsonUrlReport, error) {
 	return JsonUrlReport{
 		ExecutedAt: string(timeConverted),
 		Runtime:    report.Runtime.String(),
+		MetaData:   report.MetaData,
+		Summary:    convertSummaryToJsonStruct(report.Summary),
 		UrlStatus:  convertUrlStatusToJsonStuct(report.UrlStatus),
 	}, nil
 }
 
+// Internal conversion, to set time.* values as we want them to be
+func convertSummaryToJsonStruct(summary *ReportSummary) *JsonReportSummary {
+	if summary == nil {
+		return nil
+	}
+	j := JsonReportSummary{
+		ByStatusCode:      summary.ByStatusCode,
+		ByFailureCategory: summary.ByFailureCategory,
+		ByHost:            []JsonHostSummary{},
+		Slowest:           []JsonSlowUrl{},
+		ResponseTime: JsonResponseTimeSummary{
+			Min:    summary.ResponseTime.Min.String(),
+			Median: summary.ResponseTime.Median.String(),
+			P95:    summary.ResponseTime.P95.String(),
+			Max:    summary.ResponseTime.Max.String(),
+		},
+	}
+	for _, h := range summary.ByHost {
+		j.ByHost = append(j.ByHost, JsonHostSummary{Host: h.Host, Total: h.Total, Broken: h.Broken})
+	}
+	for _, s := range summary.Slowest {
+		j.Slowest = append(j.Slowest, JsonSlowUrl{Url: s.Url, ResponseTime: s.ResponseTime.String()})
+	}
+	return &j
+}
+
 // Internal conversion, to set time.* values as we want them to be
 func convertUrlStatusToJsonStuct(status []UrlStatus) []JsonUrlStatus {
 	jsonUrlStatus := []JsonUrlStatus{}
 	for _, u := range status {
 		j := JsonUrlStatus{
-			Url:           u.Url,
-			IsReachable:   u.IsReachable,
-			StatusMessage: u.StatusMessage,
-			ContentLength: u.ContentLength,
-			ResponseTime:  u.ResponseTime.String(),
-			NumOccured:    u.NumOccured,
+			Url:             u.Url,
+			IsReachable:     u.IsReachable,
+			StatusMessage:   u.StatusMessage,
+			ContentLength:   u.ContentLength,
+			ResponseTime:    u.ResponseTime.String(),
+			NumOccured:      u.NumOccured,
+			Sources:         u.Sources,
+			Redirects:       u.Redirects,
+			StatusCode:      u.StatusCode,
+			FailureCategory: u.FailureCategory,
+			Ignored:         u.Ignored,
+			IgnoreReason:    u.IgnoreReason,
+			Skipped:         u.Skipped,
+			Rules:           u.Rules,
 		}
 		jsonUrlStatus = append(jsonUrlStatus, j)
 	}
@@ -0,0 +1,112 @@
+package url
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Escapes data of a GitHub Actions workflow command
+var githubDataEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+
+// Escapes property values of a GitHub Actions workflow command
+var githubPropertyEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+
+// Converts UrlReport to GitHub Actions workflow commands: an error for each location of a broken url
+// and a warning for each location of a reachable url that redirects.
+func (r *UrlReport) GithubAnnotations() string {
+	var builder strings.Builder
+	for _, s := range r.UrlStatus {
+		switch {
+		case s.Ignored, s.Skipped:
+		case !s.IsReachable:
+			title := "Broken link"
+			if s.FailureCategory != CategoryNone {
+				title += " (" + string(s.FailureCategory) + ")"
+			}
+			writeGithubCommands(&builder, "error", title, s, fmt.Sprintf("%s: %s", s.Url, s.StatusMessage))
+		case len(s.Redirects) > 0:
+			writeGithubCommands(&builder, "warning", "Redirected link", s, fmt.Sprintf("%s redirects to %s", s.Url, s.Redirects[len(s.Redirects)-1]))
+		}
+	}
+	return builder.String()
+}
+
+// Writes one workflow command per location of s, or one without location if its sources are unknown.
+func writeGithubCommands(builder *strings.Builder, command, title string, s UrlStatus, message string) {
+	if len(s.Sources) == 0 {
+		builder.WriteString(fmt.Sprintf("::%s title=%s::%s\n", command, githubPropertyEscaper.Replace(title), githubDataEscaper.Replace(message)))
+		return
+	}
+	for _, source := range s.Sources {
+		properties := []string{}
+		sourceMessage := message
+		if !strings.HasPrefix(source.Page, prefixHttp) && !strings.HasPrefix(source.Page, prefixHttps) {
+			properties = append(properties, "file="+githubPropertyEscaper.Replace(source.Page))
+			if source.Line > 0 {
+				properties = append(properties, fmt.Sprintf("line=%d", source.Line))
+			}
+		} else {
+			sourceMessage = fmt.Sprintf("%s (found on %s)", message, source.Page)
+		}
+		properties = append(properties, "title="+githubPropertyEscaper.Replace(title))
+		builder.WriteString(fmt.Sprintf("::%s %s::%s\n", command, strings.Join(properties, ","), githubDataEscaper.Replace(sourceMessage)))
+	}
+}
+
+// Creates a markdown summary of the report for the job summary page of GitHub Actions.
+func (r *UrlReport) GithubStepSummary() string {
+	internal, external := r.countBroken()
+	redirected := []UrlStatus{}
+	for _, s := range r.UrlStatus {
+		if s.IsReachable && len(s.Redirects) > 0 {
+			redirected = append(redirected, s)
+		}
+	}
+
+	var builder strings.Builder
+	builder.WriteString("## blcheck report\n\n")
+	builder.WriteString(fmt.Sprintf("Checked %d urls in %s: %d broken (%d internal, %d external), %d redirected.\n",
+		len(r.UrlStatus), r.Runtime.Round(time.Millisecond), internal+external, internal, external, len(redirected)))
+
+	if internal+external > 0 {
+		builder.WriteString("\n### Broken links\n\n")
+		builder.WriteString("| Url | Status | Category | Found at |\n|---|---|---|---|\n")
+		for _, s := range r.UrlStatus {
+			if s.IsBroken() {
+				builder.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n",
+					markdownCellEscaper.Replace(s.Url),
+					markdownCellEscaper.Replace(s.StatusMessage),
+					markdownCellEscaper.Replace(string(s.FailureCategory)),
+					markdownCellEscaper.Replace(sourceLocations(s))))
+			}
+		}
+	}
+	if len(redirected) > 0 {
+		builder.WriteString("\n### Redirected links\n\n")
+		builder.WriteString("| Url | Redirects to | Found at |\n|---|---|---|\n")
+		for _, s := range redirected {
+			builder.WriteString(fmt.Sprintf("| %s | %s | %s |\n",
+				markdownCellEscaper.Replace(s.Url),
+				markdownCellEscaper.Replace(s.Redirects[len(s.Redirects)-1]),
+				markdownCellEscaper.Replace(sourceLocations(s))))
+		}
+	}
+	return builder.String()
+}
+
+// Appends GithubStepSummary to the file GitHub Actions gives in $GITHUB_STEP_SUMMARY. Does nothing outside of GitHub Actions.
+func (r *UrlReport) WriteGithubStepSummary() error {
+	summaryFile := os.Getenv("GITHUB_STEP_SUMMARY")
+	if summaryFile == "" {
+		return nil
+	}
+	file, err := os.OpenFile(summaryFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = file.WriteString(r.GithubStepSummary())
+	return err
+}
@@ -0,0 +1,84 @@
+package url
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func githubTestReport() UrlReport {
+	return NewUrlReport(time.Now(), time.Second, []UrlStatus{
+		{
+			Url:         "https://www.google.de",
+			IsReachable: true,
+			Sources:     []UrlSource{{Page: "docs/index.md", Line: 1}},
+		}, {
+			Url:         "https://www.google.de/old",
+			IsReachable: true,
+			Redirects:   []string{"https://www.google.de/new", "https://www.google.de/newer"},
+			Sources:     []UrlSource{{Page: "https://a.de"}},
+		}, {
+			Url:             "http://localhost/setup.md#install",
+			IsReachable:     false,
+			StatusMessage:   "Anchor #install not found",
+			FailureCategory: CategoryMissingAnchor,
+			Sources:         []UrlSource{{Page: "docs/index.md", Line: 3}, {Page: "docs/a,b.md"}},
+		}, {
+			Url:           "https://www.google.de/a|b",
+			IsReachable:   false,
+			StatusMessage: "Dry run\nsecond line",
+		},
+	})
+}
+
+func TestGithubAnnotations(t *testing.T) {
+	report := githubTestReport()
+	got := report.GithubAnnotations()
+	want := `::warning title=Redirected link::https://www.google.de/old redirects to https://www.google.de/newer (found on https://a.de)
+::error file=docs/index.md,line=3,title=Broken link (missing_anchor)::http://localhost/setup.md#install: Anchor #install not found
+::error file=docs/a%2Cb.md,title=Broken link (missing_anchor)::http://localhost/setup.md#install: Anchor #install not found
+::error title=Broken link::https://www.google.de/a|b: Dry run%0Asecond line
+`
+	if got != want {
+		t.Errorf("got\n%s\nexpected\n%s", got, want)
+	}
+}
+
+func TestGithubStepSummary(t *testing.T) {
+	report := githubTestReport()
+	got := report.GithubStepSummary()
+	for _, want := range []string{
+		"Checked 4 urls in 1s: 2 broken (1 internal, 1 external), 1 redirected.",
+		"| http://localhost/setup.md#install | Anchor #install not found | missing\\_anchor | docs/index.md:3, docs/a,b.md |",
+		"| https://www.google.de/a\\|b | Dry run second line |  |  |",
+		"| https://www.google.de/old | https://www.google.de/newer | https://a.de |",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected summary\n%s\nto contain %q", got, want)
+		}
+	}
+}
+
+func TestWriteGithubStepSummary(t *testing.T) {
+	report := githubTestReport()
+	t.Run("nothing to do outside of GitHub Actions", func(t *testing.T) {
+		t.Setenv("GITHUB_STEP_SUMMARY", "")
+		if err := report.WriteGithubStepSummary(); err != nil {
+			t.Errorf("did not expect an error, got %v", err)
+		}
+	})
+	t.Run("appends to summary file", func(t *testing.T) {
+		summaryFile := filepath.Join(t.TempDir(), "summary.md")
+		os.WriteFile(summaryFile, []byte("previous step\n"), 0644)
+		t.Setenv("GITHUB_STEP_SUMMARY", summaryFile)
+		if err := report.WriteGithubStepSummary(); err != nil {
+			t.Fatalf("did not expect an error, got %v", err)
+		}
+		content, _ := os.ReadFile(summaryFile)
+		if !strings.HasPrefix(string(content), "previous step\n## blcheck report") {
+			t.Errorf("expected summary to be appended, got %q", content)
+		}
+	})
+}
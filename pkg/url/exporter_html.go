@@ -0,0 +1,216 @@
+package url
+
+import (
+	"bytes"
+	"html/template"
+	"time"
+)
+
+// Data of a UrlReport prepared for the html template
+type htmlReport struct {
+	ExecutedAt     string
+	Runtime        string
+	Total          int
+	Reachable      int
+	Broken         int
+	BrokenInternal int
+	MetaData       []htmlMetaData
+	Rows           []htmlRow
+	StatusGroups   []htmlStatusGroup
+}
+
+// Meta data entry of the html report
+type htmlMetaData struct {
+	Key   string
+	Value string
+}
+
+// One UrlStatus as row of the html report
+type htmlRow struct {
+	UrlStatus
+	ResponseTimeMs int64
+}
+
+// UrlStatus with the same status message
+type htmlStatusGroup struct {
+	StatusMessage string
+	IsReachable   bool
+	Urls          []string
+}
+
+// Converts UrlReport to a self contained html page, without any external assets.
+func (r *UrlReport) Html() (string, error) {
+	buf := bytes.Buffer{}
+	err := htmlReportTemplate.Execute(&buf, convertToHtmlReport(*r))
+	if err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// Internal conversion, to precompute everything the template shows
+func convertToHtmlReport(report UrlReport) htmlReport {
+	internal, external := report.countBroken()
+	h := htmlReport{
+		ExecutedAt:     report.ExecutedAt.Format(time.RFC3339),
+		Runtime:        report.Runtime.Round(time.Millisecond).String(),
+		Total:          len(report.UrlStatus),
+		Broken:         internal + external,
+		BrokenInternal: internal,
+	}
+	h.Reachable = h.Total - h.Broken - report.countSkipped()
+
+	for _, k := range report.sortedMetaDataKeys() {
+		h.MetaData = append(h.MetaData, htmlMetaData{Key: k, Value: report.MetaData[k]})
+	}
+
+	groupPositions := make(map[string]int)
+	for _, s := range report.UrlStatus {
+		h.Rows = append(h.Rows, htmlRow{UrlStatus: s, ResponseTimeMs: s.ResponseTime.Milliseconds()})
+		pos, found := groupPositions[s.StatusMessage]
+		if !found {
+			pos = len(h.StatusGroups)
+			groupPositions[s.StatusMessage] = pos
+			h.StatusGroups = append(h.StatusGroups, htmlStatusGroup{StatusMessage: s.StatusMessage, IsReachable: s.IsReachable})
+		}
+		h.StatusGroups[pos].Urls = append(h.StatusGroups[pos].Urls, s.Url)
+	}
+	return h
+}
+
+var htmlReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>blcheck report {{.ExecutedAt}}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2em; color: #222; background: #fafafa; }
+h1 { font-size: 1.6em; }
+.cards { display: flex; flex-wrap: wrap; gap: 1em; margin-bottom: 1.5em; }
+.card { background: #fff; border: 1px solid #ddd; border-radius: 6px; padding: 1em 1.5em; min-width: 8em; }
+.card .value { font-size: 1.8em; font-weight: bold; }
+.card.broken .value { color: #c0392b; }
+.card.reachable .value { color: #27ae60; }
+table { border-collapse: collapse; width: 100%; background: #fff; }
+th, td { border: 1px solid #ddd; padding: 0.4em 0.6em; text-align: left; vertical-align: top; }
+th { background: #eee; cursor: pointer; user-select: none; }
+th.sorted-asc::after { content: " \25B2"; }
+th.sorted-desc::after { content: " \25BC"; }
+tr.broken td:first-child { border-left: 4px solid #c0392b; }
+tr.reachable td:first-child { border-left: 4px solid #27ae60; }
+tr.ignored td:first-child { border-left: 4px solid #999; }
+tr.skipped td:first-child { border-left: 4px solid #ccc; }
+td.url { word-break: break-all; }
+.filters { margin: 1em 0; display: flex; gap: 1em; }
+.filters input { flex: 1; padding: 0.4em; }
+details { margin: 0.3em 0; }
+summary { cursor: pointer; }
+ul { margin: 0.3em 0; padding-left: 1.5em; }
+</style>
+</head>
+<body>
+<h1>blcheck report</h1>
+<p>Started: {{.ExecutedAt}}, took: {{.Runtime}}</p>
+<div class="cards">
+<div class="card"><div>Checked urls</div><div class="value">{{.Total}}</div></div>
+<div class="card reachable"><div>Reachable</div><div class="value">{{.Reachable}}</div></div>
+<div class="card broken"><div>Broken</div><div class="value">{{.Broken}}</div></div>
+<div class="card broken"><div>Broken internal</div><div class="value">{{.BrokenInternal}}</div></div>
+</div>
+{{if .MetaData}}<details>
+<summary>Meta information</summary>
+<ul>{{range .MetaData}}<li>{{.Key}}: {{.Value}}</li>{{end}}</ul>
+</details>{{end}}
+
+<h2>By status</h2>
+{{range .StatusGroups}}<details>
+<summary>{{if .StatusMessage}}{{.StatusMessage}}{{else}}Unknown{{end}} ({{len .Urls}})</summary>
+<ul>{{range .Urls}}<li>{{.}}</li>{{end}}</ul>
+</details>
+{{end}}
+
+<h2>Urls</h2>
+<div class="filters">
+<input id="filter" type="search" placeholder="Filter urls, status messages and categories">
+<select id="state">
+<option value="">All</option>
+<option value="broken">Broken</option>
+<option value="reachable">Reachable</option>
+<option value="ignored">Ignored</option>
+<option value="skipped">Skipped</option>
+</select>
+</div>
+<table id="urls">
+<thead><tr>
+<th data-type="text">url</th>
+<th data-type="text">is_reachable</th>
+<th data-type="text">status_message</th>
+<th data-type="number">status_code</th>
+<th data-type="text">failure_category</th>
+<th data-type="number">content_length</th>
+<th data-type="number">response_time</th>
+<th data-type="number">num_occured</th>
+<th data-type="text">details</th>
+</tr></thead>
+<tbody>
+{{range .Rows}}<tr class="{{if .IsReachable}}reachable{{else if .Ignored}}ignored{{else if .Skipped}}skipped{{else}}broken{{end}}">
+<td class="url" data-value="{{.Url}}">{{.Url}}</td>
+<td data-value="{{.IsReachable}}">{{.IsReachable}}</td>
+<td data-value="{{.StatusMessage}}">{{.StatusMessage}}</td>
+<td data-value="{{.StatusCode}}">{{if .StatusCode}}{{.StatusCode}}{{end}}</td>
+<td data-value="{{.FailureCategory}}">{{.FailureCategory}}</td>
+<td data-value="{{.ContentLength}}">{{.ContentLength}}</td>
+<td data-value="{{.ResponseTimeMs}}">{{.ResponseTime}}</td>
+<td data-value="{{.NumOccured}}">{{.NumOccured}}</td>
+<td data-value="">{{if or .Redirects .Sources (not .IsReachable)}}<details>
+<summary>show</summary>
+{{if .Skipped}}<div>{{.StatusMessage}}</div>{{else if not .IsReachable}}<div>Error: {{.StatusMessage}}</div>{{end}}
+{{if .Ignored}}<div>Ignored: {{.IgnoreReason}}</div>{{end}}
+{{if .Redirects}}<div>Redirects:</div><ul>{{range .Redirects}}<li>{{.}}</li>{{end}}</ul>{{end}}
+{{if .Sources}}<div>Referrers:</div><ul>{{range .Sources}}<li>{{.}}</li>{{end}}</ul>{{end}}
+</details>{{end}}</td>
+</tr>
+{{end}}</tbody>
+</table>
+<script>
+(function () {
+  var table = document.getElementById("urls");
+  var body = table.tBodies[0];
+  var filter = document.getElementById("filter");
+  var state = document.getElementById("state");
+
+  function applyFilter() {
+    var text = filter.value.toLowerCase();
+    Array.prototype.forEach.call(body.rows, function (row) {
+      var matchesText = row.textContent.toLowerCase().indexOf(text) >= 0;
+      var matchesState = state.value === "" || row.classList.contains(state.value);
+      row.style.display = matchesText && matchesState ? "" : "none";
+    });
+  }
+  filter.addEventListener("input", applyFilter);
+  state.addEventListener("change", applyFilter);
+
+  Array.prototype.forEach.call(table.tHead.rows[0].cells, function (header, column) {
+    header.addEventListener("click", function () {
+      var ascending = !header.classList.contains("sorted-asc");
+      Array.prototype.forEach.call(table.tHead.rows[0].cells, function (h) {
+        h.classList.remove("sorted-asc", "sorted-desc");
+      });
+      header.classList.add(ascending ? "sorted-asc" : "sorted-desc");
+      var numeric = header.dataset.type === "number";
+      var rows = Array.prototype.slice.call(body.rows);
+      rows.sort(function (a, b) {
+        var x = a.cells[column].dataset.value;
+        var y = b.cells[column].dataset.value;
+        var result = numeric ? Number(x) - Number(y) : x.localeCompare(y);
+        return ascending ? result : -result;
+      });
+      rows.forEach(function (row) { body.appendChild(row); });
+    });
+  });
+})();
+</script>
+</body>
+</html>
+`))
@@ -0,0 +1,55 @@
+package url
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHtml(t *testing.T) {
+	report := NewUrlReport(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 10*time.Second, []UrlStatus{
+		{
+			Url:           "https://www.google.de",
+			IsReachable:   true,
+			StatusMessage: "OK",
+			ContentLength: 1000,
+			ResponseTime:  5 * time.Second,
+			NumOccured:    1,
+			Redirects:     []string{"https://www.google.de/start"},
+		}, {
+			Url:             "https://www.google.de/<script>",
+			IsReachable:     false,
+			StatusMessage:   "Not Found",
+			StatusCode:      404,
+			FailureCategory: CategoryHttpStatus,
+			ContentLength:   -1,
+			NumOccured:      2,
+			Sources:         []UrlSource{{Page: "docs/README.md", Line: 3}},
+		},
+	})
+	report.AddMetaData("total_extracted_urls", "2")
+
+	got, err := report.Html()
+	if err != nil {
+		t.Fatalf("did not expect an error, got %v", err)
+	}
+	for _, want := range []string{
+		"<!DOCTYPE html>",
+		`<div>Checked urls</div><div class="value">2</div>`,
+		`<div>Broken</div><div class="value">1</div>`,
+		"total_extracted_urls: 2",
+		"Not Found (1)",
+		"<li>https://www.google.de/start</li>",
+		"<li>docs/README.md:3</li>",
+		"https://www.google.de/&lt;script&gt;",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected html report to contain %q", want)
+		}
+	}
+	for _, external := range []string{"<link ", "<script src", "@import"} {
+		if strings.Contains(got, external) {
+			t.Errorf("expected html report to not load external assets, found %q", external)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package url
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Name of the testsuite for urls without a known source page
+const junitUnknownSourceSuite = "unknown source"
+
+// Root element of a JUnit xml report
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+// All checked urls of one source page
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+// One checked url
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+}
+
+// Reason a broken url is ignored
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// Reason a url is not reachable
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Details string `xml:",chardata"`
+}
+
+// Converts UrlReport to JUnit xml, with one testsuite per source page and one testcase per url.
+func (r *UrlReport) JUnit() (string, error) {
+	xmlBytes, err := xml.MarshalIndent(convertToJUnitStruct(*r), "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return xml.Header + string(xmlBytes) + "\n", nil
+}
+
+// Internal conversion, to group UrlStatus by source page
+func convertToJUnitStruct(report UrlReport) junitTestSuites {
+	sourceGroups := report.GroupBySource()
+	withoutSource := []UrlStatus{}
+	for _, s := range report.UrlStatus {
+		if len(s.Sources) == 0 {
+			withoutSource = append(withoutSource, s)
+		}
+	}
+	if len(withoutSource) > 0 {
+		sourceGroups = append(sourceGroups, SourceGroup{Page: junitUnknownSourceSuite, UrlStatus: withoutSource})
+	}
+
+	suites := junitTestSuites{Name: "blcheck", Time: junitSeconds(report.Runtime), Suites: []junitTestSuite{}}
+	for _, group := range sourceGroups {
+		suite := junitTestSuite{Name: group.Page, Timestamp: report.ExecutedAt.Format("2006-01-02T15:04:05")}
+		var suiteTime time.Duration
+		for _, s := range group.UrlStatus {
+			testCase := junitTestCase{Name: s.Url, ClassName: group.Page, Time: junitSeconds(s.ResponseTime)}
+			switch {
+			case s.Ignored:
+				testCase.Skipped = &junitSkipped{Message: s.IgnoreReason}
+				suite.Skipped += 1
+			case s.Skipped:
+				testCase.Skipped = &junitSkipped{Message: s.StatusMessage}
+				suite.Skipped += 1
+			case !s.IsReachable:
+				testCase.Failure = &junitFailure{
+					Message: s.StatusMessage,
+					Type:    string(s.FailureCategory),
+					Details: junitFailureDetails(s, group.Page),
+				}
+				suite.Failures += 1
+			}
+			suiteTime += s.ResponseTime
+			suite.TestCases = append(suite.TestCases, testCase)
+		}
+		suite.Tests = len(suite.TestCases)
+		suite.Time = junitSeconds(suiteTime)
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Suites = append(suites.Suites, suite)
+	}
+	return suites
+}
+
+// Describes why a url failed and where it was found on the page.
+func junitFailureDetails(s UrlStatus, page string) string {
+	lines := []string{"status message: " + s.StatusMessage}
+	if s.StatusCode != 0 {
+		lines = append(lines, fmt.Sprintf("status code: %d", s.StatusCode))
+	}
+	if s.FailureCategory != CategoryNone {
+		lines = append(lines, "failure category: "+string(s.FailureCategory))
+	}
+	if locations := s.locationsOnPage(page); len(locations) > 0 {
+		lines = append(lines, "found at: "+strings.Join(locations, ", "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Formats duration as seconds with millisecond precision, as JUnit time attributes expect.
+func junitSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
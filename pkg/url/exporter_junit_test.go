@@ -0,0 +1,83 @@
+package url
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJUnit(t *testing.T) {
+	report := NewUrlReport(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 10*time.Second, []UrlStatus{
+		{
+			Url:           "https://www.google.de",
+			IsReachable:   true,
+			StatusMessage: "OK",
+			ResponseTime:  1500 * time.Millisecond,
+			Sources:       []UrlSource{{Page: "https://a.de"}, {Page: "https://b.de"}},
+		}, {
+			Url:             "https://www.google.de/missing",
+			IsReachable:     false,
+			StatusMessage:   "Not Found",
+			StatusCode:      404,
+			FailureCategory: CategoryHttpStatus,
+			ResponseTime:    250 * time.Millisecond,
+			Sources:         []UrlSource{{Page: "docs/README.md", Line: 3}},
+		}, {
+			Url:           "https://www.google.de/given",
+			IsReachable:   true,
+			StatusMessage: "OK",
+		},
+	})
+
+	got, err := report.JUnit()
+	if err != nil {
+		t.Fatalf("did not expect an error, got %v", err)
+	}
+	var suites junitTestSuites
+	if err := xml.Unmarshal([]byte(got), &suites); err != nil {
+		t.Fatalf("expected valid xml, got %v", err)
+	}
+
+	t.Run("counts tests and failures", func(t *testing.T) {
+		if suites.Tests != 4 || suites.Failures != 1 || suites.Time != "10.000" {
+			t.Errorf("got tests=%d failures=%d time=%s", suites.Tests, suites.Failures, suites.Time)
+		}
+	})
+	t.Run("one testsuite per source page", func(t *testing.T) {
+		want := []string{"docs/README.md", "https://a.de", "https://b.de", junitUnknownSourceSuite}
+		if len(suites.Suites) != len(want) {
+			t.Fatalf("got %d testsuites expected %d", len(suites.Suites), len(want))
+		}
+		for i, suite := range suites.Suites {
+			if suite.Name != want[i] {
+				t.Errorf("got testsuite %q expected %q", suite.Name, want[i])
+			}
+		}
+	})
+	t.Run("time from response time", func(t *testing.T) {
+		testCase := suites.Suites[1].TestCases[0]
+		if testCase.Name != "https://www.google.de" || testCase.Time != "1.500" {
+			t.Errorf("got testcase %q with time %s", testCase.Name, testCase.Time)
+		}
+	})
+	t.Run("failure with status details", func(t *testing.T) {
+		failure := suites.Suites[0].TestCases[0].Failure
+		if failure == nil {
+			t.Fatal("expected a failure")
+		}
+		if failure.Message != "Not Found" || failure.Type != string(CategoryHttpStatus) {
+			t.Errorf("got message %q type %q", failure.Message, failure.Type)
+		}
+		for _, want := range []string{"status code: 404", "failure category: http_status", "found at: docs/README.md:3"} {
+			if !strings.Contains(failure.Details, want) {
+				t.Errorf("expected failure details %q to contain %q", failure.Details, want)
+			}
+		}
+	})
+	t.Run("no failure for reachable urls", func(t *testing.T) {
+		if suites.Suites[3].TestCases[0].Failure != nil {
+			t.Error("expected no failure")
+		}
+	})
+}
@@ -0,0 +1,126 @@
+package url
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Heading of broken urls without a failure category, e.g. in dry runs
+const markdownUncategorized = "uncategorized"
+
+// Escapes text for a cell of a markdown table
+var markdownCellEscaper = strings.NewReplacer(
+	`\`, `\\`, "|", `\|`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`, "<", "&lt;", ">", "&gt;", "\r", " ", "\n", " ",
+)
+
+// Converts UrlReport to markdown, with a summary, broken urls grouped by failure category and collapsed reachable urls.
+func (r *UrlReport) Markdown() string {
+	internal, external := r.countBroken()
+	var builder strings.Builder
+	builder.WriteString("# blcheck report\n\n")
+	builder.WriteString("## Summary\n\n")
+	builder.WriteString(fmt.Sprintf("- Started: %s\n", r.ExecutedAt.Format(time.RFC3339)))
+	builder.WriteString(fmt.Sprintf("- Took: %s\n", r.Runtime.Round(time.Millisecond)))
+	builder.WriteString(fmt.Sprintf("- Checked urls: %d\n", len(r.UrlStatus)))
+	skipped := r.countSkipped()
+	builder.WriteString(fmt.Sprintf("- Reachable urls: %d\n", len(r.UrlStatus)-internal-external-skipped))
+	builder.WriteString(fmt.Sprintf("- Broken urls: %d (internal references: %d, external urls: %d)\n", internal+external, internal, external))
+	if skipped > 0 {
+		builder.WriteString(fmt.Sprintf("- Skipped urls: %d\n", skipped))
+	}
+	if len(r.MetaData) > 0 {
+		builder.WriteString("\n<details>\n<summary>Meta information</summary>\n\n")
+		for _, k := range r.sortedMetaDataKeys() {
+			builder.WriteString(fmt.Sprintf("- %s: %s\n", markdownCellEscaper.Replace(k), markdownCellEscaper.Replace(r.MetaData[k])))
+		}
+		builder.WriteString("\n</details>\n")
+	}
+
+	if internal+external > 0 {
+		builder.WriteString("\n## Broken links\n")
+		for _, category := range append(slices.Clone(FailureCategories), CategoryNone) {
+			broken := r.brokenWithCategory(category)
+			if len(broken) == 0 {
+				continue
+			}
+			heading := string(category)
+			if category == CategoryNone {
+				heading = markdownUncategorized
+			}
+			builder.WriteString(fmt.Sprintf("\n### %s (%d)\n\n", markdownCellEscaper.Replace(heading), len(broken)))
+			builder.WriteString("| Url | Status | Status code | Found at |\n|---|---|---|---|\n")
+			for _, s := range broken {
+				statusCode := ""
+				if s.StatusCode != 0 {
+					statusCode = fmt.Sprint(s.StatusCode)
+				}
+				builder.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n",
+					markdownCellEscaper.Replace(s.Url),
+					markdownCellEscaper.Replace(s.StatusMessage),
+					statusCode,
+					markdownCellEscaper.Replace(sourceLocations(s))))
+			}
+		}
+	}
+
+	ignored := []UrlStatus{}
+	for _, s := range r.UrlStatus {
+		if !s.IsReachable && s.Ignored {
+			ignored = append(ignored, s)
+		}
+	}
+	if len(ignored) > 0 {
+		builder.WriteString(fmt.Sprintf("\n## Ignored links (%d)\n\n", len(ignored)))
+		builder.WriteString("| Url | Status | Reason | Found at |\n|---|---|---|---|\n")
+		for _, s := range ignored {
+			builder.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n",
+				markdownCellEscaper.Replace(s.Url),
+				markdownCellEscaper.Replace(s.StatusMessage),
+				markdownCellEscaper.Replace(s.IgnoreReason),
+				markdownCellEscaper.Replace(sourceLocations(s))))
+		}
+	}
+
+	reachable := []UrlStatus{}
+	for _, s := range r.UrlStatus {
+		if s.IsReachable {
+			reachable = append(reachable, s)
+		}
+	}
+	if len(reachable) > 0 {
+		builder.WriteString("\n## Reachable links\n\n")
+		builder.WriteString(fmt.Sprintf("<details>\n<summary>%d reachable links</summary>\n\n", len(reachable)))
+		builder.WriteString("| Url | Status | Response time | Found at |\n|---|---|---|---|\n")
+		for _, s := range reachable {
+			builder.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n",
+				markdownCellEscaper.Replace(s.Url),
+				markdownCellEscaper.Replace(s.StatusMessage),
+				s.ResponseTime.Round(time.Millisecond),
+				markdownCellEscaper.Replace(sourceLocations(s))))
+		}
+		builder.WriteString("\n</details>\n")
+	}
+	return builder.String()
+}
+
+// All broken UrlStatus with failure category.
+func (r UrlReport) brokenWithCategory(category FailureCategory) []UrlStatus {
+	broken := []UrlStatus{}
+	for _, s := range r.UrlStatus {
+		if s.IsBroken() && s.FailureCategory == category {
+			broken = append(broken, s)
+		}
+	}
+	return broken
+}
+
+// All locations a url was found at, comma separated.
+func sourceLocations(s UrlStatus) string {
+	locations := []string{}
+	for _, source := range s.Sources {
+		locations = append(locations, source.String())
+	}
+	return strings.Join(locations, ", ")
+}
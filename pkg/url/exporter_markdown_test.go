@@ -0,0 +1,79 @@
+package url
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMarkdown(t *testing.T) {
+	report := NewUrlReport(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 10*time.Second, []UrlStatus{
+		{
+			Url:           "https://www.google.de",
+			IsReachable:   true,
+			StatusMessage: "OK",
+			ResponseTime:  1500 * time.Millisecond,
+			Sources:       []UrlSource{{Page: "https://a.de"}},
+		}, {
+			Url:             "https://www.google.de/a|b",
+			IsReachable:     false,
+			StatusMessage:   "Not Found",
+			StatusCode:      404,
+			FailureCategory: CategoryHttpStatus,
+			Sources:         []UrlSource{{Page: "docs/my_page.md", Line: 3}},
+		}, {
+			Url:             "http://localhost/setup.md#install",
+			IsReachable:     false,
+			StatusMessage:   "Anchor #install not found",
+			FailureCategory: CategoryMissingAnchor,
+		}, {
+			Url:           "https://www.google.de/dry",
+			IsReachable:   false,
+			StatusMessage: "Dry run <b>",
+		},
+	})
+	report.AddMetaData("checked_urls", "https://a.de")
+
+	got := report.Markdown()
+
+	t.Run("summary", func(t *testing.T) {
+		for _, want := range []string{
+			"- Started: 2024-01-01T00:00:00Z\n",
+			"- Checked urls: 4\n",
+			"- Reachable urls: 1\n",
+			"- Broken urls: 3 (internal references: 1, external urls: 2)\n",
+			"- checked\\_urls: https://a.de\n",
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("expected markdown to contain %q", want)
+			}
+		}
+	})
+	t.Run("broken links grouped by category", func(t *testing.T) {
+		headings := []string{"### missing\\_anchor (1)", "### http\\_status (1)", "### uncategorized (1)"}
+		last := -1
+		for _, heading := range headings {
+			pos := strings.Index(got, heading)
+			if pos <= last {
+				t.Errorf("expected heading %q after previous heading", heading)
+			}
+			last = pos
+		}
+	})
+	t.Run("escapes special characters", func(t *testing.T) {
+		for _, want := range []string{
+			"| https://www.google.de/a\\|b | Not Found | 404 | docs/my\\_page.md:3 |",
+			"| https://www.google.de/dry | Dry run &lt;b&gt; |  |  |",
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("expected markdown to contain %q", want)
+			}
+		}
+	})
+	t.Run("reachable links collapsed", func(t *testing.T) {
+		want := "<details>\n<summary>1 reachable links</summary>\n\n| Url | Status | Response time | Found at |\n|---|---|---|---|\n| https://www.google.de | OK | 1.5s | https://a.de |\n\n</details>\n"
+		if !strings.HasSuffix(got, want) {
+			t.Errorf("got\n%s\nexpected suffix\n%s", got, want)
+		}
+	})
+}
@@ -0,0 +1,60 @@
+package url
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Last line of NDJSON output
+type ndjsonSummaryLine struct {
+	Summary ndjsonSummary `json:"summary"`
+}
+
+// Information about the whole check, without UrlStatus
+type ndjsonSummary struct {
+	ExecutedAt string            `json:"executed_at"`
+	Runtime    string            `json:"runtime"`
+	MetaData   map[string]string `json:"meta_data,omitempty"`
+}
+
+// Writes UrlStatus as newline delimited JSON, one object per line, while checks are still running.
+type NdjsonWriter struct {
+	w                io.Writer
+	includeReachable bool
+}
+
+// Creates NdjsonWriter, reachable urls are skipped unless includeReachable is set.
+func NewNdjsonWriter(w io.Writer, includeReachable bool) *NdjsonWriter {
+	return &NdjsonWriter{w: w, includeReachable: includeReachable}
+}
+
+// Writes one UrlStatus as JSON line.
+func (n *NdjsonWriter) WriteStatus(s UrlStatus) error {
+	if s.IsReachable && !n.includeReachable {
+		return nil
+	}
+	return n.writeLine(convertUrlStatusToJsonStuct([]UrlStatus{s})[0])
+}
+
+// Writes the summary line with executed time, runtime and MetaData of the report.
+func (n *NdjsonWriter) WriteSummary(r UrlReport) error {
+	jsonReport, err := convertToJsonStruct(r)
+	if err != nil {
+		return err
+	}
+	return n.writeLine(ndjsonSummaryLine{Summary: ndjsonSummary{
+		ExecutedAt: jsonReport.ExecutedAt,
+		Runtime:    jsonReport.Runtime,
+		MetaData:   jsonReport.MetaData,
+	}})
+}
+
+// Writes value as single JSON line.
+func (n *NdjsonWriter) writeLine(value any) error {
+	jsonBytes, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	_, err = n.w.Write(append(jsonBytes, '\n'))
+	return err
+}
@@ -0,0 +1,33 @@
+package url
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestNdjsonWriter(t *testing.T) {
+	reachable := UrlStatus{Url: "https://www.google.de", IsReachable: true, StatusMessage: "OK", ResponseTime: time.Second, NumOccured: 1}
+	broken := UrlStatus{Url: "https://www.google.de/missing", IsReachable: false, StatusMessage: "Not Found", StatusCode: 404, FailureCategory: CategoryHttpStatus, NumOccured: 2}
+
+	t.Run("skips reachable urls", func(t *testing.T) {
+		buf := bytes.Buffer{}
+		n := NewNdjsonWriter(&buf, false)
+		n.WriteStatus(reachable)
+		n.WriteStatus(broken)
+		want := `{"url":"https://www.google.de/missing","is_reachable":false,"status_message":"Not Found","content_length":0,"response_time":"0s","num_occured":2,"status_code":404,"failure_category":"http_status"}` + "\n"
+		if buf.String() != want {
+			t.Errorf("got %q expected %q", buf.String(), want)
+		}
+	})
+	t.Run("writes summary line", func(t *testing.T) {
+		buf := bytes.Buffer{}
+		report := NewUrlReport(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 10*time.Second, []UrlStatus{})
+		report.AddMetaData("broken_urls", "0")
+		NewNdjsonWriter(&buf, true).WriteSummary(report)
+		want := `{"summary":{"executed_at":"2024-01-01T00:00:00Z","runtime":"10s","meta_data":{"broken_urls":"0"}}}` + "\n"
+		if buf.String() != want {
+			t.Errorf("got %q expected %q", buf.String(), want)
+		}
+	})
+}
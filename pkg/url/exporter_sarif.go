@@ -0,0 +1,220 @@
+package url
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	sarifVersion   = "2.1.0"
+	sarifSchema    = "https://json.schemastore.org/sarif-2.1.0.json"
+	blcheckRepoUri = "https://github.com/Felixs/blcheck"
+	// Rule of broken urls without a failure category
+	sarifRuleBrokenLink = "broken_link"
+	// Base of the file uris, the root of the checked out repository
+	sarifSourceRoot = "%SRCROOT%"
+)
+
+// Description and help text of the SARIF rule for each failure category
+var sarifRules = []sarifRule{
+	{
+		Id:               string(CategoryMissingFile),
+		ShortDescription: sarifText{Text: "Link to a missing local file"},
+		Help:             sarifText{Text: "The linked file does not exist in the checked directory. Fix the path of the link, restore the file or add a redirect if it was moved on purpose."},
+	}, {
+		Id:               string(CategoryMissingAnchor),
+		ShortDescription: sarifText{Text: "Link to a missing anchor"},
+		Help:             sarifText{Text: "The linked file exists, but has no heading, id or name attribute matching the anchor. Check for renamed headings and update the fragment of the link."},
+	}, {
+		Id:               string(CategoryHttpStatus),
+		ShortDescription: sarifText{Text: "Url answers with an error status"},
+		Help:             sarifText{Text: "The server answered with an error status code like 404 or 500. Update the link to the new location of the page or remove it if the page is gone."},
+	}, {
+		Id:               string(CategoryTimeout),
+		ShortDescription: sarifText{Text: "Url did not answer in time"},
+		Help:             sarifText{Text: "The server did not answer within the response timeout. Check if the server is down or slow, or raise the timeout with -max-response-timeout."},
+	}, {
+		Id:               string(CategoryConnectionError),
+		ShortDescription: sarifText{Text: "Url could not be connected to"},
+		Help:             sarifText{Text: "No connection to the server could be established, e.g. the host name does not resolve or the certificate is invalid. Fix the host name of the link or remove it."},
+	}, {
+		Id:               sarifRuleBrokenLink,
+		ShortDescription: sarifText{Text: "Broken link"},
+		Help:             sarifText{Text: "The url could not be checked successfully. Look at the result message for details."},
+	},
+}
+
+// Root element of a SARIF log
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+// One run of blcheck
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+// Tool that created the results
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+// Description of blcheck and its rules
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationUri string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+// One failure category as SARIF rule
+type sarifRule struct {
+	Id               string    `json:"id"`
+	ShortDescription sarifText `json:"shortDescription"`
+	Help             sarifText `json:"help"`
+}
+
+// Plain text message
+type sarifText struct {
+	Text string `json:"text"`
+}
+
+// One broken link at one location
+type sarifResult struct {
+	RuleId       string             `json:"ruleId"`
+	Level        string             `json:"level"`
+	Message      sarifText          `json:"message"`
+	Locations    []sarifLocation    `json:"locations,omitempty"`
+	Suppressions []sarifSuppression `json:"suppressions,omitempty"`
+}
+
+// Ignore rule a broken link matched
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Justification string `json:"justification,omitempty"`
+}
+
+// Location a broken link was found at
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+// File and line a broken link was found at
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+// File a broken link was found in, relative to UriBaseId
+type sarifArtifactLocation struct {
+	Uri       string `json:"uri"`
+	UriBaseId string `json:"uriBaseId"`
+}
+
+// Line a broken link was found on
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// Converts UrlReport to a SARIF 2.1.0 log, with one result per location of each broken url in a file
+// below the working directory.
+func (r *UrlReport) Sarif() (string, error) {
+	root, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	jsonBytes, err := json.MarshalIndent(convertToSarifStruct(*r, root), "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(jsonBytes), nil
+}
+
+// Internal conversion, to map broken urls to results of rules. Code scanning only accepts results in files
+// of the repository, so locations on web pages or outside of root and urls that were not checked are left out.
+func convertToSarifStruct(report UrlReport, root string) sarifLog {
+	results := []sarifResult{}
+	for _, s := range report.UrlStatus {
+		if s.IsReachable || s.Skipped || s.StatusMessage == dryRunStatusMessage {
+			continue
+		}
+		ruleId := string(s.FailureCategory)
+		if s.FailureCategory == CategoryNone {
+			ruleId = sarifRuleBrokenLink
+		}
+		message := sarifText{Text: sarifMessage(s)}
+		var suppressions []sarifSuppression
+		if s.Ignored {
+			suppressions = []sarifSuppression{{Kind: "external", Justification: s.IgnoreReason}}
+		}
+		for _, source := range s.Sources {
+			location, ok := sarifLocationOf(source, root)
+			if !ok {
+				continue
+			}
+			results = append(results, sarifResult{
+				RuleId:       ruleId,
+				Level:        "error",
+				Message:      message,
+				Locations:    []sarifLocation{location},
+				Suppressions: suppressions,
+			})
+		}
+	}
+
+	return sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "blcheck",
+				Version:        report.MetaData["blcheck_version"],
+				InformationUri: blcheckRepoUri,
+				Rules:          sarifRules,
+			}},
+			Results: results,
+		}},
+	}
+}
+
+// Describes broken url with its status.
+func sarifMessage(s UrlStatus) string {
+	if s.StatusCode != 0 {
+		return fmt.Sprintf("Broken link %s: %s (status code %d)", s.Url, s.StatusMessage, s.StatusCode)
+	}
+	return fmt.Sprintf("Broken link %s: %s", s.Url, s.StatusMessage)
+}
+
+// Location of source as uri relative to root, false if source is a web page or a file outside of root.
+func sarifLocationOf(source UrlSource, root string) (sarifLocation, bool) {
+	if strings.HasPrefix(source.Page, prefixHttp) || strings.HasPrefix(source.Page, prefixHttps) {
+		return sarifLocation{}, false
+	}
+	path := source.Page
+	if filepath.IsAbs(path) {
+		relativePath, err := filepath.Rel(root, path)
+		if err != nil {
+			return sarifLocation{}, false
+		}
+		path = relativePath
+	}
+	path = filepath.ToSlash(filepath.Clean(path))
+	if path == ".." || strings.HasPrefix(path, "../") {
+		return sarifLocation{}, false
+	}
+	uri := (&url.URL{Path: path}).EscapedPath()
+	location := sarifLocation{PhysicalLocation: sarifPhysicalLocation{
+		ArtifactLocation: sarifArtifactLocation{Uri: uri, UriBaseId: sarifSourceRoot},
+	}}
+	if source.Line > 0 {
+		location.PhysicalLocation.Region = &sarifRegion{StartLine: source.Line}
+	}
+	return location, true
+}
@@ -0,0 +1,113 @@
+package url
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSarif(t *testing.T) {
+	root, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := NewUrlReport(time.Now(), time.Second, []UrlStatus{
+		{
+			Url:         "https://www.google.de",
+			IsReachable: true,
+			Sources:     []UrlSource{{Page: "docs/index.md", Line: 1}},
+		}, {
+			Url:             "http://localhost/docs/setup.md#install",
+			IsReachable:     false,
+			StatusMessage:   "Anchor #install not found",
+			FailureCategory: CategoryMissingAnchor,
+			Sources: []UrlSource{
+				{Page: "docs/index.md", Line: 3},
+				{Page: "./README.md", Line: 12},
+				{Page: filepath.Join(root, "docs", "getting started.md"), Line: 7},
+				{Page: filepath.Join(filepath.Dir(root), "elsewhere.md"), Line: 1},
+			},
+		}, {
+			Url:             "https://www.google.de/missing",
+			IsReachable:     false,
+			StatusMessage:   "Not Found",
+			StatusCode:      404,
+			FailureCategory: CategoryHttpStatus,
+			Sources:         []UrlSource{{Page: "https://a.de"}},
+		}, {
+			Url:           "https://www.google.de/dry",
+			IsReachable:   false,
+			StatusMessage: dryRunStatusMessage,
+			Sources:       []UrlSource{{Page: "docs/index.md", Line: 5}},
+		}, {
+			Url:           "https://www.google.de/skipped",
+			IsReachable:   false,
+			StatusMessage: "Skipped by rule social",
+			Skipped:       true,
+			Sources:       []UrlSource{{Page: "docs/index.md", Line: 6}},
+		},
+	})
+	report.AddMetaData("blcheck_version", "0.0.2")
+
+	got, err := report.Sarif()
+	if err != nil {
+		t.Fatalf("did not expect an error, got %v", err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal([]byte(got), &log); err != nil {
+		t.Fatalf("expected valid json, got %v", err)
+	}
+
+	t.Run("version and tool", func(t *testing.T) {
+		driver := log.Runs[0].Tool.Driver
+		if log.Version != "2.1.0" || driver.Name != "blcheck" || driver.Version != "0.0.2" {
+			t.Errorf("got version %q tool %q %q", log.Version, driver.Name, driver.Version)
+		}
+	})
+	t.Run("rule with help text for each category", func(t *testing.T) {
+		rules := make(map[string]bool)
+		for _, rule := range log.Runs[0].Tool.Driver.Rules {
+			if rule.Help.Text == "" {
+				t.Errorf("expected help text for rule %s", rule.Id)
+			}
+			rules[rule.Id] = true
+		}
+		for _, category := range []FailureCategory{CategoryMissingFile, CategoryMissingAnchor, CategoryHttpStatus, CategoryTimeout, CategoryConnectionError} {
+			if !rules[string(category)] {
+				t.Errorf("expected rule for category %s", category)
+			}
+		}
+	})
+	t.Run("one result per location of broken urls in files", func(t *testing.T) {
+		location := func(uri string, line int) []sarifLocation {
+			return []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{Uri: uri, UriBaseId: "%SRCROOT%"},
+				Region:           &sarifRegion{StartLine: line},
+			}}}
+		}
+		message := sarifText{Text: "Broken link http://localhost/docs/setup.md#install: Anchor #install not found"}
+		want := []sarifResult{
+			{RuleId: "missing_anchor", Level: "error", Message: message, Locations: location("docs/index.md", 3)},
+			{RuleId: "missing_anchor", Level: "error", Message: message, Locations: location("README.md", 12)},
+			{RuleId: "missing_anchor", Level: "error", Message: message, Locations: location("docs/getting%20started.md", 7)},
+		}
+		if !reflect.DeepEqual(want, log.Runs[0].Results) {
+			t.Errorf("got %+v expected %+v", log.Runs[0].Results, want)
+		}
+	})
+	t.Run("results have relative file locations", func(t *testing.T) {
+		for _, result := range log.Runs[0].Results {
+			if len(result.Locations) == 0 {
+				t.Fatalf("expected a location, got %+v", result)
+			}
+			uri := result.Locations[0].PhysicalLocation.ArtifactLocation.Uri
+			if strings.Contains(uri, "://") || strings.HasPrefix(uri, "/") || strings.HasPrefix(uri, "..") {
+				t.Errorf("expected uri relative to the repository, got %q", uri)
+			}
+		}
+	})
+}
@@ -85,6 +85,26 @@ func TestConvertToJsonStruct(t *testing.T) {
 	}
 }
 
+func TestJsonRedirects(t *testing.T) {
+	report := UrlReport{
+		ExecutedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		UrlStatus: []UrlStatus{
+			{Url: "http://a.de", IsReachable: true, Redirects: []string{"https://a.de", "https://www.a.de/"}},
+			{Url: "https://b.de", IsReachable: true},
+		},
+	}
+	got, err := report.Json()
+	if err != nil {
+		t.Fatal("did not expect an error")
+	}
+	if !strings.Contains(got, `"redirects":["https://a.de","https://www.a.de/"]`) {
+		t.Errorf("expected redirects in %s", got)
+	}
+	if strings.Count(got, `"redirects"`) != 1 {
+		t.Errorf("expected redirects to be omitted for urls without redirects, got %s", got)
+	}
+}
+
 func TestCsv(t *testing.T) {
 	t.Run("one line", func(t *testing.T) {
 		r := UrlReport{
@@ -100,7 +120,7 @@ func TestCsv(t *testing.T) {
 			},
 		}
 		got, err := r.Csv(false)
-		want := `https://www.google.de,true,OK,1000,1s,12
+		want := `https://www.google.de,true,OK,1000,1s,12,,false,,false
 `
 		if err != nil {
 			t.Fatal("did not expect to get an error")
@@ -132,8 +152,8 @@ func TestCsv(t *testing.T) {
 			},
 		}
 		got, err := r.Csv(false)
-		want := `https://www.google.de,true,OK,1000,1s,12
-https://www.google2.de,false,Not Found,-1,1m0s,99
+		want := `https://www.google.de,true,OK,1000,1s,12,,false,,false
+https://www.google2.de,false,Not Found,-1,1m0s,99,,false,,false
 `
 		if err != nil {
 			t.Fatal("did not expect to get an error")
@@ -157,8 +177,8 @@ https://www.google2.de,false,Not Found,-1,1m0s,99
 			},
 		}
 		got, err := r.Csv(true)
-		want := `url,is_reachable,status_message,content_length,response_time,num_occured
-https://www.google.de,true,OK,1000,1s,12
+		want := `url,is_reachable,status_message,content_length,response_time,num_occured,failure_category,ignored,ignore_reason,skipped
+https://www.google.de,true,OK,1000,1s,12,,false,,false
 `
 		if err != nil {
 			t.Fatal("did not expect to get an error")
@@ -168,6 +188,37 @@ https://www.google.de,true,OK,1000,1s,12
 			t.Errorf("got %q want %q", got, want)
 		}
 	})
+	t.Run("ignored and skipped urls", func(t *testing.T) {
+		r := UrlReport{
+			UrlStatus: []UrlStatus{
+				{
+					Url:             "https://www.linkedin.com/in/someone",
+					StatusMessage:   "Status 999",
+					StatusCode:      999,
+					FailureCategory: CategoryHttpStatus,
+					Ignored:         true,
+					IgnoreReason:    "answers with 999",
+					NumOccured:      1,
+				}, {
+					Url:           "https://www.facebook.com/page",
+					StatusMessage: "Skipped by rule social",
+					ContentLength: -1,
+					NumOccured:    1,
+					Skipped:       true,
+				},
+			},
+		}
+		got, err := r.Csv(false)
+		want := `https://www.linkedin.com/in/someone,false,Status 999,0,0s,1,http_status,true,answers with 999,false
+https://www.facebook.com/page,false,Skipped by rule social,-1,0s,1,,false,,true
+`
+		if err != nil {
+			t.Fatal("did not expect to get an error")
+		}
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	})
 	t.Run("check quote comma", func(t *testing.T) {
 		r := UrlReport{
 			UrlStatus: []UrlStatus{
@@ -182,7 +233,7 @@ https://www.google.de,true,OK,1000,1s,12
 			},
 		}
 		got, err := r.Csv(false)
-		want := `"https://www,google.de",true,"O,K",1000,1s,12
+		want := `"https://www,google.de",true,"O,K",1000,1s,12,,false,,false
 `
 		if err != nil {
 			t.Fatal("did not expect to get an error")
@@ -193,3 +244,45 @@ https://www.google.de,true,OK,1000,1s,12
 		}
 	})
 }
+
+func TestJsonWithMetaData(t *testing.T) {
+	report := NewUrlReport(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 10*time.Second, []UrlStatus{})
+	report.AddMetaData("total_extracted_urls", "0")
+
+	got, err := report.Json()
+	want := `{"executed_at":"2024-01-01T00:00:00Z","runtime":"10s","meta_data":{"total_extracted_urls":"0"},"url_status":[]}`
+	if err != nil {
+		t.Fatal("did not expect an error")
+	}
+	if got != want {
+		t.Errorf("got %q expected %q", got, want)
+	}
+}
+
+func TestCsvWithMetaData(t *testing.T) {
+	r := NewUrlReport(time.Now(), time.Second, []UrlStatus{
+		{
+			Url:           "https://www.google.de",
+			IsReachable:   true,
+			StatusMessage: "OK",
+			ContentLength: 1000,
+			ResponseTime:  time.Second,
+			NumOccured:    12,
+		},
+	})
+	r.AddMetaData("checked_urls", "https://a.de")
+	r.AddMetaData("blcheck_version", "0.0.2")
+
+	got, err := r.CsvWithMetaData(true)
+	want := `# blcheck_version: 0.0.2
+# checked_urls: https://a.de
+url,is_reachable,status_message,content_length,response_time,num_occured,failure_category,ignored,ignore_reason,skipped
+https://www.google.de,true,OK,1000,1s,12,,false,,false
+`
+	if err != nil {
+		t.Fatal("did not expect to get an error")
+	}
+	if got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+}
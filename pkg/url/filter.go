@@ -0,0 +1,141 @@
+package url
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Include or exclude rule of a UrlFilter.
+type FilterRule struct {
+	Pattern string
+	Include bool
+	regex   *regexp.Regexp
+}
+
+// Creates FilterRule from a regular expression, or from a plain substring if literal is set.
+func NewFilterRule(pattern string, include, literal bool) (FilterRule, error) {
+	expression := pattern
+	if literal {
+		expression = regexp.QuoteMeta(pattern)
+	}
+	regex, err := regexp.Compile(expression)
+	if err != nil {
+		return FilterRule{}, fmt.Errorf("invalid filter %q: %w", pattern, err)
+	}
+	return FilterRule{Pattern: pattern, Include: include, regex: regex}, nil
+}
+
+// Checks if rule matches any part of url.
+func (r FilterRule) Matches(url string) bool {
+	return r.regex.MatchString(url)
+}
+
+// String representation of FilterRule, e.g. exclude "\.pdf$".
+func (r FilterRule) String() string {
+	if r.Include {
+		return fmt.Sprintf("include %q", r.Pattern)
+	}
+	return fmt.Sprintf("exclude %q", r.Pattern)
+}
+
+// Decides which urls are kept. Urls matching any exclude rule are removed,
+// if include rules are given urls need to match at least one of them.
+type UrlFilter struct {
+	Rules []FilterRule
+}
+
+// Creates UrlFilter from regular expressions, or from plain substrings if literal is set.
+func NewUrlFilter(includes, excludes []string, literal bool) (UrlFilter, error) {
+	filter := UrlFilter{Rules: []FilterRule{}}
+	for _, pattern := range excludes {
+		rule, err := NewFilterRule(pattern, false, literal)
+		if err != nil {
+			return UrlFilter{}, err
+		}
+		filter.Rules = append(filter.Rules, rule)
+	}
+	for _, pattern := range includes {
+		rule, err := NewFilterRule(pattern, true, literal)
+		if err != nil {
+			return UrlFilter{}, err
+		}
+		filter.Rules = append(filter.Rules, rule)
+	}
+	return filter, nil
+}
+
+// Checks if filter has no rules and keeps all urls.
+func (f UrlFilter) IsEmpty() bool {
+	return len(f.Rules) == 0
+}
+
+// Checks if url is kept by the filter. Returns the index of the exclude rule that removed it,
+// or -1 if it was kept or did not match any include rule.
+func (f UrlFilter) Allows(url string) (allowed bool, removedBy int) {
+	hasIncludes := false
+	includeMatched := false
+	for i, rule := range f.Rules {
+		switch {
+		case rule.Include:
+			hasIncludes = true
+			includeMatched = includeMatched || rule.Matches(url)
+		case rule.Matches(url):
+			return false, i
+		}
+	}
+	return !hasIncludes || includeMatched, -1
+}
+
+// Creates empty FilterStats to count removed urls of the filter.
+func (f UrlFilter) NewStats() FilterStats {
+	return FilterStats{filter: f, removed: make([]int, len(f.Rules))}
+}
+
+// Number of urls removed by each rule of a UrlFilter.
+type FilterStats struct {
+	filter UrlFilter
+	// removed urls per exclude rule
+	removed []int
+	// removed urls that did not match any include rule
+	notIncluded int
+}
+
+// Checks if url is kept by the filter and counts it for the rule that removed it.
+func (s *FilterStats) Allows(url string) bool {
+	allowed, removedBy := s.filter.Allows(url)
+	switch {
+	case allowed:
+	case removedBy >= 0:
+		s.removed[removedBy] += 1
+	default:
+		s.notIncluded += 1
+	}
+	return allowed
+}
+
+// Number of all removed urls.
+func (s FilterStats) Total() int {
+	total := s.notIncluded
+	for _, removed := range s.removed {
+		total += removed
+	}
+	return total
+}
+
+// Lists how many urls each exclude rule and all include rules together removed.
+func (s FilterStats) String() string {
+	parts := []string{}
+	includes := []string{}
+	for i, rule := range s.filter.Rules {
+		if rule.Include {
+			includes = append(includes, fmt.Sprintf("%q", rule.Pattern))
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s: %d", rule, s.removed[i]))
+	}
+	if len(includes) > 0 {
+		parts = append(parts, fmt.Sprintf("include %s: %d", strings.Join(includes, " or "), s.notIncluded))
+	}
+	return strings.Join(parts, ", ")
+}
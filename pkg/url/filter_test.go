@@ -0,0 +1,91 @@
+package url
+
+import "testing"
+
+func TestUrlFilter(t *testing.T) {
+	t.Run("regular expressions", func(t *testing.T) {
+		filter, err := NewUrlFilter([]string{`^https://`}, []string{`\.pdf$`}, false)
+		if err != nil {
+			t.Fatalf("did not expect an error, got %v", err)
+		}
+		cases := []struct {
+			url  string
+			want bool
+		}{
+			{"https://a.de/page", true},
+			{"http://a.de/page", false},
+			{"https://a.de/file.pdf", false},
+			{"https://a.de/file.pdf.html", true},
+		}
+		for _, tt := range cases {
+			if got, _ := filter.Allows(tt.url); got != tt.want {
+				t.Errorf("got %v for %q expected %v", got, tt.url, tt.want)
+			}
+		}
+	})
+	t.Run("literal rules", func(t *testing.T) {
+		filter, err := NewUrlFilter(nil, []string{"a.de/(x"}, true)
+		if err != nil {
+			t.Fatalf("did not expect an error, got %v", err)
+		}
+		if allowed, _ := filter.Allows("https://a.de/(x)"); allowed {
+			t.Error("expected literal exclude to remove url")
+		}
+		if allowed, _ := filter.Allows("https://a.de/x"); !allowed {
+			t.Error("expected url without literal match to be allowed")
+		}
+	})
+	t.Run("repeated includes match any rule", func(t *testing.T) {
+		filter, _ := NewUrlFilter([]string{"a\\.de", "b\\.de"}, nil, false)
+		for _, u := range []string{"https://a.de", "https://b.de"} {
+			if allowed, _ := filter.Allows(u); !allowed {
+				t.Errorf("expected %q to be allowed", u)
+			}
+		}
+		if allowed, _ := filter.Allows("https://c.de"); allowed {
+			t.Error("expected url matching no include to be removed")
+		}
+	})
+	t.Run("invalid regular expression", func(t *testing.T) {
+		if _, err := NewUrlFilter(nil, []string{"("}, false); err == nil {
+			t.Error("expected an error")
+		}
+	})
+	t.Run("empty filter allows everything", func(t *testing.T) {
+		filter, _ := NewUrlFilter(nil, nil, false)
+		if !filter.IsEmpty() {
+			t.Error("expected filter to be empty")
+		}
+		if allowed, _ := filter.Allows("https://a.de"); !allowed {
+			t.Error("expected url to be allowed")
+		}
+	})
+}
+
+func TestFilterStats(t *testing.T) {
+	filter, _ := NewUrlFilter([]string{"a\\.de", "b\\.de"}, []string{"/private", "/admin"}, false)
+	urls := []ExtractedUrl{
+		{Url: "https://a.de/"},
+		{Url: "https://a.de/private"},
+		{Url: "https://b.de/admin"},
+		{Url: "https://b.de/private/admin"},
+		{Url: "https://c.de/"},
+	}
+	stats := filter.NewStats()
+	filtered := []ExtractedUrl{}
+	for _, u := range urls {
+		if stats.Allows(u.Url) {
+			filtered = append(filtered, u)
+		}
+	}
+	if len(filtered) != 1 || filtered[0].Url != "https://a.de/" {
+		t.Errorf("got %v expected only https://a.de/", filtered)
+	}
+	if stats.Total() != 4 {
+		t.Errorf("got %d removed urls expected 4", stats.Total())
+	}
+	want := `exclude "/private": 2, exclude "/admin": 1, include "a\\.de" or "b\\.de": 1`
+	if stats.String() != want {
+		t.Errorf("got %q expected %q", stats.String(), want)
+	}
+}
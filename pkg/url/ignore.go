@@ -0,0 +1,163 @@
+package url
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// File with ignore rules that is used if it exists in the working directory
+const DefaultIgnoreFile = ".blcheckignore"
+
+// Layout of expiry dates in ignore files
+const ignoreExpiryLayout = "2006-01-02"
+
+// Known broken urls that should not fail a check, until the rule expires.
+type IgnoreRule struct {
+	// Url pattern, * matches any characters
+	Pattern string
+	// Rule does not apply after this day, zero if it never expires
+	Expires time.Time
+	Reason  string
+	// Line of the rule in the ignore file
+	Line  int
+	regex *regexp.Regexp
+}
+
+// All rules of an ignore file.
+type IgnoreRules []IgnoreRule
+
+// Creates IgnoreRule for url pattern, * matches any characters.
+func NewIgnoreRule(pattern string, expires time.Time, reason string) IgnoreRule {
+	regex := "^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*") + "$"
+	return IgnoreRule{Pattern: pattern, Expires: expires, Reason: reason, regex: regexp.MustCompile(regex)}
+}
+
+// Checks if url matches the pattern of the rule, ignoring case like extracted urls do.
+func (r IgnoreRule) Matches(url string) bool {
+	return r.regex.MatchString(url) || r.regex.MatchString(strings.ToLower(url))
+}
+
+// Checks if rule expired before now. A rule is valid until the end of its expiry day.
+func (r IgnoreRule) IsExpired(now time.Time) bool {
+	return !r.Expires.IsZero() && !now.Before(r.Expires.AddDate(0, 0, 1))
+}
+
+// String representation of IgnoreRule as written in ignore files.
+func (r IgnoreRule) String() string {
+	fields := []string{r.Pattern}
+	if !r.Expires.IsZero() {
+		fields = append(fields, r.Expires.Format(ignoreExpiryLayout))
+	}
+	if r.Reason != "" {
+		fields = append(fields, "# "+r.Reason)
+	}
+	return strings.Join(fields, " ")
+}
+
+// Reads ignore rules from file at path.
+func ReadIgnoreFile(path string) (IgnoreRules, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	rules, err := ParseIgnoreRules(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return rules, nil
+}
+
+// Parses one rule per line: url pattern, optional expiry date and optional reason after #.
+// Empty lines and lines starting with # are skipped.
+//
+//	https://www.linkedin.com/*  # blocks requests from CI
+//	https://old.example.com/*  2024-12-31  # tracked in issue 42
+func ParseIgnoreRules(r io.Reader) (IgnoreRules, error) {
+	rules := IgnoreRules{}
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber += 1
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		reason := ""
+		if pos := strings.Index(line, " #"); pos >= 0 {
+			reason = strings.TrimSpace(line[pos+2:])
+			line = line[:pos]
+		}
+		fields := strings.Fields(line)
+		if len(fields) > 2 {
+			return nil, fmt.Errorf("line %d: expected url pattern and optional expiry date, got %q", lineNumber, line)
+		}
+		var expires time.Time
+		if len(fields) == 2 {
+			var err error
+			expires, err = time.Parse(ignoreExpiryLayout, fields[1])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid expiry date %q, use YYYY-MM-DD", lineNumber, fields[1])
+			}
+		}
+		rule := NewIgnoreRule(fields[0], expires, reason)
+		rule.Line = lineNumber
+		rules = append(rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// Rules that expired before now.
+func (rules IgnoreRules) Expired(now time.Time) IgnoreRules {
+	expired := IgnoreRules{}
+	for _, rule := range rules {
+		if rule.IsExpired(now) {
+			expired = append(expired, rule)
+		}
+	}
+	return expired
+}
+
+// Writes a warning to w for each rule that expired before now, urls matching them are reported as broken again.
+func (rules IgnoreRules) WarnExpired(w io.Writer, source string, now time.Time) {
+	for _, rule := range rules.Expired(now) {
+		fmt.Fprintf(w, "WARNING: Ignore rule %q in %s:%d expired on %s, matching urls are reported as broken again\n",
+			rule.Pattern, source, rule.Line, rule.Expires.Format(time.DateOnly))
+	}
+}
+
+// Marks broken UrlStatus as ignored if it matches a rule that is not expired at now.
+func (rules IgnoreRules) Apply(s UrlStatus, now time.Time) UrlStatus {
+	if s.IsReachable || s.Skipped {
+		return s
+	}
+	for _, rule := range rules {
+		if !rule.IsExpired(now) && rule.Matches(s.Url) {
+			s.Ignored = true
+			s.IgnoreReason = rule.Reason
+			if s.IgnoreReason == "" {
+				s.IgnoreReason = "matches " + rule.Pattern
+			}
+			return s
+		}
+	}
+	return s
+}
+
+// Marks all broken UrlStatus of report as ignored that match a rule not expired at now.
+func (rules IgnoreRules) ApplyToReport(r UrlReport, now time.Time) UrlReport {
+	urlStatus := []UrlStatus{}
+	for _, s := range r.UrlStatus {
+		urlStatus = append(urlStatus, rules.Apply(s, now))
+	}
+	r.UrlStatus = urlStatus
+	return r
+}
@@ -0,0 +1,166 @@
+package url
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseIgnoreRules(t *testing.T) {
+	t.Run("patterns with expiry dates and reasons", func(t *testing.T) {
+		rules, err := ParseIgnoreRules(strings.NewReader(`# known broken urls
+
+https://www.linkedin.com/*  # answers with 999 in CI
+https://old.example.com/page 2024-12-31 # tracked in issue 42
+https://a.de/#anchor
+`))
+		if err != nil {
+			t.Fatalf("did not expect an error, got %v", err)
+		}
+		want := []string{
+			"https://www.linkedin.com/* # answers with 999 in CI",
+			"https://old.example.com/page 2024-12-31 # tracked in issue 42",
+			"https://a.de/#anchor",
+		}
+		if len(rules) != len(want) {
+			t.Fatalf("got %d rules expected %d", len(rules), len(want))
+		}
+		for i, rule := range rules {
+			if rule.String() != want[i] {
+				t.Errorf("got rule %q expected %q", rule.String(), want[i])
+			}
+		}
+		if rules[1].Line != 4 {
+			t.Errorf("got line %d expected 4", rules[1].Line)
+		}
+	})
+	t.Run("invalid expiry date", func(t *testing.T) {
+		if _, err := ParseIgnoreRules(strings.NewReader("https://a.de 31.12.2024\n")); err == nil {
+			t.Error("expected an error")
+		}
+	})
+	t.Run("too many fields", func(t *testing.T) {
+		if _, err := ParseIgnoreRules(strings.NewReader("https://a.de 2024-12-31 reason without hash\n")); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
+
+func TestIgnoreRule(t *testing.T) {
+	rule := NewIgnoreRule("https://www.linkedin.com/*", time.Time{}, "")
+	cases := []struct {
+		url  string
+		want bool
+	}{
+		{"https://www.linkedin.com/in/someone", true},
+		{"https://www.LinkedIn.com/in/someone", true},
+		{"https://www.linkedin.com.evil.de/", false},
+		{"http://www.linkedin.com/in/someone", false},
+	}
+	for _, tt := range cases {
+		if got := rule.Matches(tt.url); got != tt.want {
+			t.Errorf("match of %s got %v expected %v", tt.url, got, tt.want)
+		}
+	}
+
+	t.Run("valid until end of expiry day", func(t *testing.T) {
+		rule := NewIgnoreRule("https://a.de", time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), "")
+		if rule.IsExpired(time.Date(2024, 12, 31, 23, 59, 0, 0, time.UTC)) {
+			t.Error("expected rule to be valid on expiry day")
+		}
+		if !rule.IsExpired(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) {
+			t.Error("expected rule to be expired after expiry day")
+		}
+		if NewIgnoreRule("https://a.de", time.Time{}, "").IsExpired(time.Now()) {
+			t.Error("expected rule without expiry date to never expire")
+		}
+	})
+}
+
+func TestIgnoreRulesWarnExpired(t *testing.T) {
+	rules, err := ParseIgnoreRules(strings.NewReader("https://a.de/*  2024-12-31\nhttps://b.de/*  2030-01-01\nhttps://c.de/*\n"))
+	if err != nil {
+		t.Fatalf("did not expect an error, got %v", err)
+	}
+	var out strings.Builder
+	rules.WarnExpired(&out, ".blcheckignore", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	want := "WARNING: Ignore rule \"https://a.de/*\" in .blcheckignore:1 expired on 2024-12-31, matching urls are reported as broken again\n"
+	if out.String() != want {
+		t.Errorf("got %q expected %q", out.String(), want)
+	}
+}
+
+func TestIgnoreRulesApply(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	rules := IgnoreRules{
+		NewIgnoreRule("https://www.linkedin.com/*", time.Time{}, "answers with 999"),
+		NewIgnoreRule("https://old.de/*", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), "moved"),
+		NewIgnoreRule("https://b.de", time.Time{}, ""),
+	}
+	report := rules.ApplyToReport(NewUrlReport(now, time.Second, []UrlStatus{
+		{Url: "https://www.linkedin.com/in/someone", IsReachable: false, Sources: []UrlSource{{Page: "docs/team.md", Line: 4}}},
+		{Url: "https://www.linkedin.com/ok", IsReachable: true},
+		{Url: "https://old.de/page", IsReachable: false},
+		{Url: "https://b.de", IsReachable: false},
+	}), now)
+
+	wantIgnored := []bool{true, false, false, true}
+	for i, s := range report.UrlStatus {
+		if s.Ignored != wantIgnored[i] {
+			t.Errorf("ignored of %s got %v expected %v", s.Url, s.Ignored, wantIgnored[i])
+		}
+	}
+	if report.UrlStatus[0].IgnoreReason != "answers with 999" || report.UrlStatus[3].IgnoreReason != "matches https://b.de" {
+		t.Errorf("got unexpected ignore reasons %q %q", report.UrlStatus[0].IgnoreReason, report.UrlStatus[3].IgnoreReason)
+	}
+	if expired := rules.Expired(now); len(expired) != 1 || expired[0].Pattern != "https://old.de/*" {
+		t.Errorf("got unexpected expired rules %v", expired)
+	}
+
+	t.Run("ignored urls do not fail the check", func(t *testing.T) {
+		if report.AllReachable() {
+			t.Error("expected expired rule to not ignore broken url")
+		}
+		withoutExpired := report
+		withoutExpired.UrlStatus = []UrlStatus{report.UrlStatus[0], report.UrlStatus[1], report.UrlStatus[3]}
+		if !withoutExpired.AllReachable() {
+			t.Error("expected ignored urls to not fail the check")
+		}
+		if internal, external := withoutExpired.countBroken(); internal+external != 0 || withoutExpired.countIgnored() != 2 {
+			t.Errorf("got broken %d ignored %d", internal+external, withoutExpired.countIgnored())
+		}
+	})
+	t.Run("reported as ignored", func(t *testing.T) {
+		if got := report.FullString(); !strings.Contains(got, "Ignored broken urls: 2\n") {
+			t.Errorf("expected text output to count ignored urls, got %q", got)
+		}
+		junit, _ := report.JUnit()
+		if !strings.Contains(junit, `<skipped message="answers with 999"></skipped>`) {
+			t.Errorf("expected ignored urls to be skipped in JUnit, got %s", junit)
+		}
+		sarif, _ := report.Sarif()
+		if !strings.Contains(sarif, `"justification": "answers with 999"`) {
+			t.Errorf("expected ignored urls to be suppressed in SARIF, got %s", sarif)
+		}
+		if strings.Contains(report.GithubAnnotations(), "linkedin") {
+			t.Error("expected no GitHub annotation for ignored urls")
+		}
+	})
+	t.Run("skipped urls are neither broken nor ignored", func(t *testing.T) {
+		skipped := rules.ApplyToReport(NewUrlReport(now, time.Second, []UrlStatus{
+			{Url: "https://www.linkedin.com/in/skipped", StatusMessage: "Skipped by rule social", Skipped: true},
+		}), now)
+		if skipped.UrlStatus[0].Ignored || !skipped.AllReachable() || skipped.countIgnored() != 0 {
+			t.Errorf("expected skipped url to be neither ignored nor broken, got %+v", skipped.UrlStatus[0])
+		}
+		if got := skipped.FullString(); !strings.Contains(got, "Skipped urls: 1\n") || strings.Contains(got, "Ignored broken urls") {
+			t.Errorf("expected text output to count skipped urls, got %q", got)
+		}
+		if sarif, _ := skipped.Sarif(); strings.Contains(sarif, "linkedin") {
+			t.Errorf("expected no SARIF result for skipped urls, got %s", sarif)
+		}
+		if skipped.GithubAnnotations() != "" || strings.Contains(skipped.Markdown(), "Ignored links") {
+			t.Error("expected skipped urls to be neither annotated nor listed as ignored")
+		}
+	})
+}
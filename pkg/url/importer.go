@@ -0,0 +1,76 @@
+package url
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Reads a report that was exported by Json from file.
+func ReadJsonReport(path string) (UrlReport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return UrlReport{}, err
+	}
+	report, err := ParseJsonReport(data)
+	if err != nil {
+		return UrlReport{}, fmt.Errorf("could not parse report %s: %w", path, err)
+	}
+	return report, nil
+}
+
+// Parses a report that was exported by Json back into a UrlReport. The summary is not imported.
+func ParseJsonReport(data []byte) (UrlReport, error) {
+	var jsonReport JsonUrlReport
+	if err := json.Unmarshal(data, &jsonReport); err != nil {
+		return UrlReport{}, err
+	}
+
+	executedAt, err := time.Parse(time.RFC3339Nano, jsonReport.ExecutedAt)
+	if err != nil {
+		return UrlReport{}, err
+	}
+	runtime, err := time.ParseDuration(jsonReport.Runtime)
+	if err != nil {
+		return UrlReport{}, err
+	}
+	urlStatus := []UrlStatus{}
+	for _, j := range jsonReport.UrlStatus {
+		s, err := convertJsonStructToUrlStatus(j)
+		if err != nil {
+			return UrlReport{}, err
+		}
+		urlStatus = append(urlStatus, s)
+	}
+
+	report := NewUrlReport(executedAt, runtime, urlStatus)
+	for k, v := range jsonReport.MetaData {
+		report.AddMetaData(k, v)
+	}
+	return report, nil
+}
+
+// Internal conversion, to parse time.* values back
+func convertJsonStructToUrlStatus(j JsonUrlStatus) (UrlStatus, error) {
+	responseTime, err := time.ParseDuration(j.ResponseTime)
+	if err != nil {
+		return UrlStatus{}, fmt.Errorf("invalid response time of %s: %w", j.Url, err)
+	}
+	return UrlStatus{
+		Url:             j.Url,
+		IsReachable:     j.IsReachable,
+		StatusMessage:   j.StatusMessage,
+		ContentLength:   j.ContentLength,
+		ResponseTime:    responseTime,
+		NumOccured:      j.NumOccured,
+		Sources:         j.Sources,
+		Redirects:       j.Redirects,
+		StatusCode:      j.StatusCode,
+		FailureCategory: j.FailureCategory,
+		Ignored:         j.Ignored,
+		IgnoreReason:    j.IgnoreReason,
+		Skipped:         j.Skipped,
+		Rules:           j.Rules,
+	}, nil
+}
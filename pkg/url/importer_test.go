@@ -0,0 +1,76 @@
+package url
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseJsonReport(t *testing.T) {
+	report := NewUrlReport(time.Date(2024, 1, 1, 12, 30, 0, 5000, time.UTC), 10*time.Second, []UrlStatus{
+		{
+			Url:           "https://www.google.de",
+			IsReachable:   true,
+			StatusMessage: "OK",
+			ContentLength: 1000,
+			ResponseTime:  1500 * time.Microsecond,
+			NumOccured:    2,
+			Sources:       []UrlSource{{Page: "docs/index.md", Line: 3}},
+			Redirects:     []string{"https://www.google.de/start"},
+			StatusCode:    200,
+		}, {
+			Url:             "https://www.google.de/missing",
+			IsReachable:     false,
+			StatusMessage:   "Not Found",
+			NumOccured:      1,
+			StatusCode:      404,
+			FailureCategory: CategoryHttpStatus,
+		},
+	})
+	report.AddMetaData("blcheck_version", "0.0.2")
+
+	t.Run("round trip of exported report", func(t *testing.T) {
+		exported, err := report.Json()
+		if err != nil {
+			t.Fatalf("did not expect an error, got %v", err)
+		}
+		got, err := ParseJsonReport([]byte(exported))
+		if err != nil {
+			t.Fatalf("did not expect an error, got %v", err)
+		}
+		if !got.ExecutedAt.Equal(report.ExecutedAt) || got.Runtime != report.Runtime {
+			t.Errorf("got executed at %v runtime %v", got.ExecutedAt, got.Runtime)
+		}
+		if !reflect.DeepEqual(report.MetaData, got.MetaData) {
+			t.Errorf("got meta data %v expected %v", got.MetaData, report.MetaData)
+		}
+		if !reflect.DeepEqual(report.UrlStatus, got.UrlStatus) {
+			t.Errorf("got %v expected %v", got.UrlStatus, report.UrlStatus)
+		}
+	})
+	t.Run("invalid json", func(t *testing.T) {
+		if _, err := ParseJsonReport([]byte(`{"executed_at":`)); err == nil {
+			t.Error("expected an error")
+		}
+	})
+	t.Run("invalid response time", func(t *testing.T) {
+		data := `{"executed_at":"2024-01-01T00:00:00Z","runtime":"1s","url_status":[{"url":"https://a.de","response_time":"fast"}]}`
+		if _, err := ParseJsonReport([]byte(data)); err == nil {
+			t.Error("expected an error")
+		}
+	})
+	t.Run("read from file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "report.json")
+		exported, _ := report.Json()
+		os.WriteFile(path, []byte(exported), 0644)
+		got, err := ReadJsonReport(path)
+		if err != nil || len(got.UrlStatus) != 2 {
+			t.Errorf("expected to read report, got %v %v", got, err)
+		}
+		if _, err := ReadJsonReport(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+			t.Error("expected an error for missing file")
+		}
+	})
+}
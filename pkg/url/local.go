@@ -0,0 +1,203 @@
+package url
+
+import (
+	"errors"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// Base url used to resolve site relative links if no other is given
+	DefaultLocalBaseUrl = "http://localhost/"
+	// File served for directory requests
+	indexFileName = "index.html"
+	// File shown for directories in repositories
+	readmeFileName = "README.md"
+)
+
+// Static site or document tree on disk, that is published under a base url.
+type LocalSite struct {
+	Root    string
+	BaseUrl *url.URL
+}
+
+// Creates LocalSite for files below root, that get published under baseUrl. Uses DefaultLocalBaseUrl if baseUrl is empty.
+func NewLocalSite(root, baseUrl string) (LocalSite, error) {
+	if baseUrl == "" {
+		baseUrl = DefaultLocalBaseUrl
+	}
+	parsedBaseUrl, err := url.Parse(baseUrl)
+	if err != nil {
+		return LocalSite{}, err
+	}
+	if !parsedBaseUrl.IsAbs() || parsedBaseUrl.Host == "" {
+		return LocalSite{}, errors.New("base url needs to be absolute, got " + baseUrl)
+	}
+	if !strings.HasSuffix(parsedBaseUrl.Path, "/") {
+		parsedBaseUrl.Path += "/"
+	}
+	return LocalSite{Root: root, BaseUrl: parsedBaseUrl}, nil
+}
+
+// Finds all supported documents (html, markdown, reStructuredText, text) in a directory or matching a glob pattern.
+// Returns the found files and the root directory of the site.
+func FindDocumentFiles(dirOrGlob string) (files []string, root string, err error) {
+	info, err := os.Stat(dirOrGlob)
+	if err == nil && info.IsDir() {
+		err = filepath.WalkDir(dirOrGlob, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && isDocumentFile(p) {
+				files = append(files, p)
+			}
+			return nil
+		})
+		return files, dirOrGlob, err
+	}
+
+	matches, err := filepath.Glob(dirOrGlob)
+	if err != nil {
+		return nil, "", err
+	}
+	for _, match := range matches {
+		if isDocumentFile(match) {
+			files = append(files, match)
+		}
+	}
+	if len(files) == 0 {
+		return nil, "", errors.New("no supported documents found in " + dirOrGlob)
+	}
+	return files, globRoot(dirOrGlob), nil
+}
+
+// Returns the directory part of a glob pattern in front of the first pattern element.
+func globRoot(pattern string) string {
+	metaPos := strings.IndexAny(pattern, "*?[")
+	if metaPos < 0 {
+		return filepath.Dir(pattern)
+	}
+	return filepath.Dir(pattern[:metaPos+1])
+}
+
+// Reads document of the site and extracts all http(s) links with the lines they were found on.
+// Links to the site itself are marked to be checked on disk.
+func (s LocalSite) ExtractUrlsFromFile(file string) ([]ExtractedUrl, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	pageUrl, err := s.PageUrl(file)
+	if err != nil {
+		return nil, err
+	}
+
+	extractedUrls := []ExtractedUrl{}
+	for _, link := range extractDocumentLinks(file, string(content)) {
+		resolvedUrl, localPath, ok := s.resolveLink(pageUrl, link.Link)
+		if !ok {
+			continue
+		}
+		extractedUrls = append(extractedUrls, ExtractedUrl{
+			Url:        resolvedUrl,
+			NumOccured: 1,
+			Sources:    []UrlSource{{Page: file, Line: link.Line}},
+			LocalPath:  localPath,
+		})
+	}
+	return MergeExtractedUrls(extractedUrls), nil
+}
+
+// Url the given file of the site is published under.
+func (s LocalSite) PageUrl(file string) (*url.URL, error) {
+	relPath, err := filepath.Rel(s.Root, file)
+	if err != nil {
+		return nil, err
+	}
+	return s.BaseUrl.ResolveReference(&url.URL{Path: filepath.ToSlash(relPath)}), nil
+}
+
+// Resolves link found on page against the page url. Returns false for links that are not http(s).
+// Local links keep their anchor, external urls get normalized.
+func (s LocalSite) resolveLink(pageUrl *url.URL, link string) (resolvedUrl, localPath string, ok bool) {
+	linkUrl, err := url.Parse(link)
+	if err != nil {
+		return "", "", false
+	}
+	absoluteUrl := pageUrl.ResolveReference(linkUrl)
+	if absoluteUrl.Scheme != "http" && absoluteUrl.Scheme != "https" {
+		return "", "", false
+	}
+	if absoluteUrl.Host != s.BaseUrl.Host || !strings.HasPrefix(absoluteUrl.Path, s.BaseUrl.Path) {
+		return normalizeUrl(absoluteUrl.String()), "", true
+	}
+	// anchors of local links are kept to check them in the linked file
+	if absoluteUrl.Fragment == "" {
+		absoluteUrl.RawFragment = ""
+	}
+	sitePath := strings.TrimPrefix(absoluteUrl.Path, s.BaseUrl.Path)
+	localPath = filepath.Join(s.Root, filepath.FromSlash(path.Clean("/"+sitePath)))
+	return absoluteUrl.String(), localPath, true
+}
+
+// Checks if the file behind a local url exists on disk and contains the anchor of the url.
+// Directories are checked for an index or readme file and paths without extension also as html file.
+func LocalFileIsAvailable(inputUrl ExtractedUrl) UrlStatus {
+	start := time.Now()
+	localPath, err := findLocalFile(inputUrl.LocalPath)
+	if err != nil {
+		return brokenLocalStatus(inputUrl, "File not found", CategoryMissingFile, start)
+	}
+	content, err := os.ReadFile(localPath)
+	if err != nil {
+		return brokenLocalStatus(inputUrl, err.Error(), CategoryMissingFile, start)
+	}
+
+	anchor := urlFragment(inputUrl.Url)
+	if anchor != "" {
+		anchors, supported := ExtractAnchors(localPath, string(content))
+		if supported && !anchors[anchor] {
+			return brokenLocalStatus(inputUrl, "Anchor #"+anchor+" not found", CategoryMissingAnchor, start)
+		}
+	}
+	return UrlStatusFromExtractedUrl(inputUrl, true, "File found", int64(len(content)), time.Since(start))
+}
+
+// Creates UrlStatus for a broken local reference.
+func brokenLocalStatus(inputUrl ExtractedUrl, statusMessage string, category FailureCategory, start time.Time) UrlStatus {
+	status := UrlStatusFromExtractedUrl(inputUrl, false, statusMessage, -1, time.Since(start))
+	status.FailureCategory = category
+	return status
+}
+
+// Returns the decoded fragment of an url, empty if there is none or url can not be parsed.
+func urlFragment(rawUrl string) string {
+	parsedUrl, err := url.Parse(rawUrl)
+	if err != nil {
+		return ""
+	}
+	return parsedUrl.Fragment
+}
+
+// Finds the file that is served for a local path.
+func findLocalFile(localPath string) (string, error) {
+	candidates := []string{localPath}
+	info, err := os.Stat(localPath)
+	if err == nil && info.IsDir() {
+		candidates = []string{filepath.Join(localPath, indexFileName), filepath.Join(localPath, readmeFileName)}
+	} else if filepath.Ext(localPath) == "" {
+		candidates = append(candidates, localPath+".html")
+	}
+	for _, candidate := range candidates {
+		info, err := os.Stat(candidate)
+		if err == nil && !info.IsDir() {
+			return candidate, nil
+		}
+	}
+	return "", fs.ErrNotExist
+}
@@ -0,0 +1,161 @@
+package url
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFindDocumentFiles(t *testing.T) {
+	root := createLocalSite(t, map[string]string{
+		"index.html":     "",
+		"docs/page.htm":  "",
+		"docs/readme.md": "",
+		"docs/logo.png":  "",
+	})
+
+	t.Run("walks directory for supported documents", func(t *testing.T) {
+		files, gotRoot, err := FindDocumentFiles(root)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		want := []string{filepath.Join(root, "docs", "page.htm"), filepath.Join(root, "docs", "readme.md"), filepath.Join(root, "index.html")}
+		if !reflect.DeepEqual(files, want) {
+			t.Errorf("got %v want %v", files, want)
+		}
+		if gotRoot != root {
+			t.Errorf("got root %s want %s", gotRoot, root)
+		}
+	})
+
+	t.Run("single file uses its directory as root", func(t *testing.T) {
+		files, gotRoot, err := FindDocumentFiles(filepath.Join(root, "index.html"))
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if !reflect.DeepEqual(files, []string{filepath.Join(root, "index.html")}) || gotRoot != root {
+			t.Errorf("got %v with root %s", files, gotRoot)
+		}
+	})
+
+	t.Run("glob pattern uses directory in front of pattern as root", func(t *testing.T) {
+		files, gotRoot, err := FindDocumentFiles(filepath.Join(root, "docs", "*"))
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		want := []string{filepath.Join(root, "docs", "page.htm"), filepath.Join(root, "docs", "readme.md")}
+		if !reflect.DeepEqual(files, want) {
+			t.Errorf("got %v want %v", files, want)
+		}
+		if gotRoot != filepath.Join(root, "docs") {
+			t.Errorf("got root %s want %s", gotRoot, filepath.Join(root, "docs"))
+		}
+	})
+
+	t.Run("glob without supported documents fails", func(t *testing.T) {
+		_, _, err := FindDocumentFiles(filepath.Join(root, "docs", "*.png"))
+		if err == nil {
+			t.Errorf("expected an error")
+		}
+	})
+}
+
+func TestLocalSiteExtractUrlsFromFile(t *testing.T) {
+	root := createLocalSite(t, map[string]string{
+		"docs/index.html": `<a href="../about/">about</a>
+<a href="https://example.com/docs/setup">setup</a>
+<a href="https://www.GOOGLE.de/#top">google</a>
+<a href="mailto:me@example.com">mail</a>
+<a href="#local">local</a>
+<a href="../about/#team">team</a>`,
+	})
+	site, err := NewLocalSite(root, "https://example.com")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	file := filepath.Join(root, "docs", "index.html")
+	got, err := site.ExtractUrlsFromFile(file)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	want := []ExtractedUrl{
+		{Url: "https://example.com/about/", NumOccured: 1, Sources: []UrlSource{{file, 1}}, LocalPath: filepath.Join(root, "about")},
+		{Url: "https://example.com/docs/setup", NumOccured: 1, Sources: []UrlSource{{file, 2}}, LocalPath: filepath.Join(root, "docs", "setup")},
+		{Url: "https://www.google.de", NumOccured: 1, Sources: []UrlSource{{file, 3}}},
+		{Url: "https://example.com/docs/index.html#local", NumOccured: 1, Sources: []UrlSource{{file, 5}}, LocalPath: filepath.Join(root, "docs", "index.html")},
+		{Url: "https://example.com/about/#team", NumOccured: 1, Sources: []UrlSource{{file, 6}}, LocalPath: filepath.Join(root, "about")},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+func TestLocalFileIsAvailable(t *testing.T) {
+	root := createLocalSite(t, map[string]string{
+		"index.html":       "home",
+		"about/index.html": "about",
+		"setup.html":       "setup",
+		"setup.txt":        "setup",
+		"guide/README.md":  "# Guide\n## Install\n",
+		"docs/.keep":       "",
+	})
+
+	cases := []struct {
+		name      string
+		url       string
+		localPath string
+		want      bool
+	}{
+		{"existing file", "http://localhost/", filepath.Join(root, "index.html"), true},
+		{"directory with index file", "http://localhost/about/", filepath.Join(root, "about"), true},
+		{"path without html extension", "http://localhost/setup", filepath.Join(root, "setup"), true},
+		{"missing file", "http://localhost/missing.html", filepath.Join(root, "missing.html"), false},
+		{"directory with readme file", "http://localhost/guide/", filepath.Join(root, "guide"), true},
+		{"directory without index file", "http://localhost/docs/", filepath.Join(root, "docs"), false},
+		{"existing heading anchor", "http://localhost/guide/#install", filepath.Join(root, "guide"), true},
+		{"missing heading anchor", "http://localhost/guide/#usage", filepath.Join(root, "guide"), false},
+		{"anchor in file type without anchors is not checked", "http://localhost/setup.txt#usage", filepath.Join(root, "setup.txt"), true},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			got := LocalFileIsAvailable(ExtractedUrl{Url: tt.url, NumOccured: 1, LocalPath: tt.localPath})
+			if got.IsReachable != tt.want {
+				t.Errorf("got %v want IsReachable=%v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Creates files with content in a temporary directory and returns the directory.
+func createLocalSite(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestLocalFileIsAvailableCategories(t *testing.T) {
+	root := createLocalSite(t, map[string]string{"README.md": "# Readme"})
+
+	missingFile := LocalFileIsAvailable(ExtractedUrl{Url: "http://localhost/setup.md", LocalPath: filepath.Join(root, "setup.md")})
+	if missingFile.FailureCategory != CategoryMissingFile {
+		t.Errorf("got category %q want %q", missingFile.FailureCategory, CategoryMissingFile)
+	}
+	missingAnchor := LocalFileIsAvailable(ExtractedUrl{Url: "http://localhost/README.md#install", LocalPath: filepath.Join(root, "README.md")})
+	if missingAnchor.FailureCategory != CategoryMissingAnchor {
+		t.Errorf("got category %q want %q", missingAnchor.FailureCategory, CategoryMissingAnchor)
+	}
+	if !missingAnchor.FailureCategory.IsInternal() {
+		t.Errorf("expected %q to be an internal category", missingAnchor.FailureCategory)
+	}
+}
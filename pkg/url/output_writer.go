@@ -2,10 +2,17 @@ package url
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 )
 
+// Prefix of report files written by WriteReportFiles
+const ReportFilePrefix = "blcheck-"
+
+// File extensions of report files written by WriteReportFiles
+var ReportFileExtensions = []string{".json", ".csv", ".html"}
+
 // Write given string in output file at filepath, overwrites any existing files.
 func WriteTo(writePath, output string) error {
 	absWritePath, err := filepath.Abs(writePath)
@@ -20,3 +27,29 @@ func WriteTo(writePath, output string) error {
 
 	return nil
 }
+
+// Writes report as json, csv and html file into directory, creates the directory if needed.
+// All files share a name based on the execution time of the report, which gets returned.
+func WriteReportFiles(dir string, r UrlReport) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	executedAt := r.ExecutedAt.UTC()
+	name := fmt.Sprintf("%s%s%03d", ReportFilePrefix, executedAt.Format("20060102T150405"), executedAt.Nanosecond()/1e6)
+
+	outputs := map[string]func() (string, error){
+		".json": r.Json,
+		".csv":  func() (string, error) { return r.Csv(true) },
+		".html": r.Html,
+	}
+	for _, ext := range ReportFileExtensions {
+		output, err := outputs[ext]()
+		if err != nil {
+			return "", err
+		}
+		if err := WriteTo(filepath.Join(dir, name+ext), output); err != nil {
+			return "", err
+		}
+	}
+	return name, nil
+}
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/Felixs/blcheck/pkg/url"
 )
@@ -38,3 +39,21 @@ func assertWrittenFile(t *testing.T, fielpath, wantedContent string) {
 		t.Errorf("got %q wanted %q", got, wantedContent)
 	}
 }
+
+func TestWriteReportFiles(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "reports")
+	report := url.NewUrlReport(time.Date(2024, 1, 1, 10, 30, 0, 5e6, time.UTC), time.Second, []url.UrlStatus{})
+
+	name, err := url.WriteReportFiles(dir, report)
+	if err != nil {
+		t.Fatalf("Got unexpected error in write report files, %v", err)
+	}
+	if name != "blcheck-20240101T103000005" {
+		t.Errorf("got name %q", name)
+	}
+	for _, ext := range url.ReportFileExtensions {
+		if _, err := os.Stat(filepath.Join(dir, name+ext)); err != nil {
+			t.Errorf("expected %s report to be written, %v", ext, err)
+		}
+	}
+}
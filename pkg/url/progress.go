@@ -0,0 +1,177 @@
+package url
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Interval the progress line is redrawn in on a terminal
+const terminalRedrawInterval = 200 * time.Millisecond
+
+// Counts of the url checks since the program started.
+type Progress struct {
+	// Urls queued for checks, grows while a crawl finds new urls
+	Total    int
+	Checked  int
+	InFlight int
+	Broken   int
+}
+
+var (
+	progressMu sync.Mutex
+	progress   Progress
+	// Broken urls matching these rules are not counted, so the count matches the report
+	progressIgnoreRules IgnoreRules
+)
+
+// Overwrites module wide ignore rules applied before broken urls are counted.
+func SetProgressIgnoreRules(rules IgnoreRules) {
+	progressMu.Lock()
+	defer progressMu.Unlock()
+	progressIgnoreRules = rules
+}
+
+// Counts of all url checks since the program started.
+func CurrentProgress() Progress {
+	progressMu.Lock()
+	defer progressMu.Unlock()
+	return progress
+}
+
+// Counts urls that get checked.
+func addQueuedUrls(n int) {
+	progressMu.Lock()
+	defer progressMu.Unlock()
+	progress.Total += n
+}
+
+// Counts check that started.
+func startedCheck() {
+	progressMu.Lock()
+	defer progressMu.Unlock()
+	progress.InFlight += 1
+}
+
+// Counts check that finished with status s.
+func finishedCheck(s UrlStatus) {
+	progressMu.Lock()
+	defer progressMu.Unlock()
+	progress.InFlight -= 1
+	progress.Checked += 1
+	if progressIgnoreRules.Apply(s, time.Now()).IsBroken() {
+		progress.Broken += 1
+	}
+}
+
+// Checked urls per second and estimated time until all queued urls are checked, zero if nothing was checked yet.
+func (p Progress) Rate(elapsed time.Duration) (perSecond float64, eta time.Duration) {
+	if p.Checked == 0 || elapsed <= 0 {
+		return 0, 0
+	}
+	perSecond = float64(p.Checked) / elapsed.Seconds()
+	eta = time.Duration(float64(p.Total-p.Checked) / perSecond * float64(time.Second))
+	return perSecond, eta.Round(time.Second)
+}
+
+// Progress as one line, like "Checked 120/450 urls, 5 in flight, 3 broken, 12.3 req/s, ETA 27s".
+func (p Progress) Line(elapsed time.Duration) string {
+	line := fmt.Sprintf("Checked %d/%d urls, %d in flight, %d broken", p.Checked, p.Total, p.InFlight, p.Broken)
+	perSecond, eta := p.Rate(elapsed)
+	if perSecond == 0 {
+		return line
+	}
+	return fmt.Sprintf("%s, %.1f req/s, ETA %s", line, perSecond, eta)
+}
+
+// Writes progress of url checks while they run: redraws a single line on a terminal,
+// otherwise writes a line every interval.
+type ProgressReporter struct {
+	output   io.Writer
+	terminal bool
+	interval time.Duration
+
+	mu    sync.Mutex
+	start time.Time
+	// Set while a line is drawn on the terminal
+	drawn bool
+	done  chan struct{}
+	wg    sync.WaitGroup
+}
+
+// Creates ProgressReporter writing to output, terminal is set if output is a terminal.
+func NewProgressReporter(output io.Writer, terminal bool, interval time.Duration) *ProgressReporter {
+	if terminal {
+		interval = terminalRedrawInterval
+	}
+	return &ProgressReporter{output: output, terminal: terminal, interval: interval}
+}
+
+// Starts writing progress until Stop is called.
+func (r *ProgressReporter) Start() {
+	r.start = time.Now()
+	r.done = make(chan struct{})
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				r.write()
+			case <-r.done:
+				return
+			}
+		}
+	}()
+}
+
+// Stops writing progress, the final progress stays visible on a terminal.
+func (r *ProgressReporter) Stop() {
+	close(r.done)
+	r.wg.Wait()
+	if r.terminal {
+		r.write()
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		fmt.Fprintln(r.output)
+		r.drawn = false
+	}
+}
+
+// Writes current progress, replaces the drawn line on a terminal.
+func (r *ProgressReporter) write() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	line := CurrentProgress().Line(time.Since(r.start))
+	if r.terminal {
+		fmt.Fprint(r.output, "\r\033[K"+line)
+		r.drawn = true
+		return
+	}
+	fmt.Fprintln(r.output, line)
+}
+
+// Returns writer to w that clears the progress line of a terminal before each write,
+// so messages are not mixed with the progress line.
+func (r *ProgressReporter) Above(w io.Writer) io.Writer {
+	return progressClearingWriter{reporter: r, output: w}
+}
+
+// Writer that clears the progress line before each write.
+type progressClearingWriter struct {
+	reporter *ProgressReporter
+	output   io.Writer
+}
+
+func (w progressClearingWriter) Write(p []byte) (int, error) {
+	w.reporter.mu.Lock()
+	defer w.reporter.mu.Unlock()
+	if w.reporter.drawn {
+		fmt.Fprint(w.reporter.output, "\r\033[K")
+		w.reporter.drawn = false
+	}
+	return w.output.Write(p)
+}
@@ -0,0 +1,91 @@
+package url
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProgressLine(t *testing.T) {
+	cases := []struct {
+		name     string
+		progress Progress
+		elapsed  time.Duration
+		want     string
+	}{
+		{"nothing checked", Progress{Total: 10, InFlight: 2}, time.Second, "Checked 0/10 urls, 2 in flight, 0 broken"},
+		{"rate and eta", Progress{Total: 100, Checked: 20, InFlight: 5, Broken: 1}, 10 * time.Second, "Checked 20/100 urls, 5 in flight, 1 broken, 2.0 req/s, ETA 40s"},
+		{"done", Progress{Total: 4, Checked: 4}, 2 * time.Second, "Checked 4/4 urls, 0 in flight, 0 broken, 2.0 req/s, ETA 0s"},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.progress.Line(tt.elapsed); got != tt.want {
+				t.Errorf("got %q expected %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// Starts test with empty progress counts, the counts of other tests are restored after it.
+func resetProgress(t *testing.T) {
+	t.Helper()
+	previous := CurrentProgress()
+	t.Cleanup(func() {
+		progressMu.Lock()
+		defer progressMu.Unlock()
+		progress = previous
+	})
+	progressMu.Lock()
+	defer progressMu.Unlock()
+	progress = Progress{}
+}
+
+func TestFinishedCheck(t *testing.T) {
+	resetProgress(t)
+	t.Cleanup(func() { SetProgressIgnoreRules(nil) })
+	SetProgressIgnoreRules(IgnoreRules{NewIgnoreRule("https://ignored.de/*", time.Time{}, "")})
+	for _, u := range []string{"https://ignored.de/page", "https://broken.de/page"} {
+		startedCheck()
+		finishedCheck(UrlStatus{Url: u})
+	}
+	startedCheck()
+	finishedCheck(UrlStatus{Url: "https://reachable.de", IsReachable: true})
+	want := Progress{Checked: 3, Broken: 1}
+	if got := CurrentProgress(); got != want {
+		t.Errorf("got %+v expected %+v", got, want)
+	}
+}
+
+func TestProgressReporter(t *testing.T) {
+	resetProgress(t)
+	t.Run("log lines", func(t *testing.T) {
+		var output strings.Builder
+		reporter := NewProgressReporter(&output, false, time.Hour)
+		reporter.Start()
+		reporter.write()
+		reporter.write()
+		reporter.Stop()
+		lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+		if len(lines) != 2 || !strings.HasPrefix(lines[0], "Checked ") || strings.Contains(output.String(), "\r") {
+			t.Errorf("expected progress lines, got %q", output.String())
+		}
+	})
+	t.Run("terminal", func(t *testing.T) {
+		var progressOutput, infoOutput strings.Builder
+		reporter := NewProgressReporter(&progressOutput, true, time.Hour)
+		// no redraws besides the explicit ones
+		reporter.interval = time.Hour
+		reporter.Start()
+		reporter.write()
+		reporter.Above(&infoOutput).Write([]byte("message\n"))
+		reporter.Stop()
+		got := progressOutput.String()
+		want := "\r\033[KChecked 0/0 urls, 0 in flight, 0 broken\r\033[K\r\033[KChecked 0/0 urls, 0 in flight, 0 broken\n"
+		if got != want {
+			t.Errorf("expected progress line to be drawn, cleared and drawn again, got %q", got)
+		}
+		if infoOutput.String() != "message\n" {
+			t.Errorf("got message %q", infoOutput.String())
+		}
+	})
+}
@@ -2,6 +2,7 @@ package url
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -10,12 +11,30 @@ import (
 // Max number of parallel routines to query webserver.
 const MaxNumParallelQueries = 5
 
+// Status message of urls that were not checked in a dry run
+const dryRunStatusMessage = "Dry run"
+
+// Functions that get every UrlStatus as soon as its check finished
+var resultListeners = []func(UrlStatus){}
+
+// Registers listener that gets called with every UrlStatus as soon as its check finished, before the report is complete.
+func AddResultListener(listener func(UrlStatus)) {
+	resultListeners = append(resultListeners, listener)
+}
+
+// Removes all listeners, later checks are not reported to them.
+func ClearResultListeners() {
+	resultListeners = []func(UrlStatus){}
+}
+
 // Information about a url check on a single webpage.
 type UrlReport struct {
 	ExecutedAt time.Time         `json:"executed_at"`
 	Runtime    time.Duration     `json:"runtime"`
 	MetaData   map[string]string `json:"meta_data"`
 	UrlStatus  []UrlStatus       `json:"url_status"`
+	// Statistics over all checked urls, set by Summarize
+	Summary *ReportSummary `json:"summary,omitempty"`
 }
 
 // Convinience constructor
@@ -43,36 +62,175 @@ func (r UrlReport) String() string {
 func (r UrlReport) FullString() string {
 	var builder strings.Builder
 	builder.WriteString(r.String() + "\n")
+	if internal, external := r.countBroken(); internal > 0 {
+		builder.WriteString(fmt.Sprintf("Broken internal references: %d, broken external urls: %d\n", internal, external))
+	}
+	if ignored := r.countIgnored(); ignored > 0 {
+		builder.WriteString(fmt.Sprintf("Ignored broken urls: %d\n", ignored))
+	}
+	if skipped := r.countSkipped(); skipped > 0 {
+		builder.WriteString(fmt.Sprintf("Skipped urls: %d\n", skipped))
+	}
+	if r.Summary != nil {
+		builder.WriteString(r.Summary.String())
+	}
 	if len(r.MetaData) > 0 {
 		builder.WriteString("Meta information:\n")
-		for k, v := range r.MetaData {
-			builder.WriteString(fmt.Sprintf("\t%s: %s\n", k, v))
+		for _, k := range r.sortedMetaDataKeys() {
+			builder.WriteString(fmt.Sprintf("\t%s: %s\n", k, r.MetaData[k]))
 		}
 	}
-	builder.WriteString("\n")
-	builder.WriteString(UrlStatusHeaderString() + "\n")
-	for _, s := range r.UrlStatus {
-		builder.WriteString(s.String() + "\n")
+	sourceGroups := r.GroupBySource()
+	if len(sourceGroups) <= 1 && !r.hasSourceLines() {
+		builder.WriteString("\n")
+		builder.WriteString(UrlStatusHeaderString() + "\n")
+		for _, s := range r.UrlStatus {
+			builder.WriteString(s.String() + "\n")
+		}
+		return builder.String()
+	}
+
+	for _, group := range sourceGroups {
+		builder.WriteString("\nSource page: " + group.Page + "\n")
+		builder.WriteString("location\t" + UrlStatusHeaderString() + "\n")
+		for _, s := range group.UrlStatus {
+			for _, location := range s.locationsOnPage(group.Page) {
+				builder.WriteString(location + "\t" + s.String() + "\n")
+			}
+		}
 	}
 
 	return builder.String()
 }
 
+// Checks if any url has a known line where it was found.
+func (r UrlReport) hasSourceLines() bool {
+	for _, s := range r.UrlStatus {
+		for _, source := range s.Sources {
+			if source.Line > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// UrlStatus that where found on the same source page.
+type SourceGroup struct {
+	Page      string
+	UrlStatus []UrlStatus
+}
+
+// Groups all UrlStatus by the pages they were found on, sorted by page. UrlStatus found on several pages are part of each group.
+func (r UrlReport) GroupBySource() []SourceGroup {
+	groupedStatus := make(map[string][]UrlStatus)
+	for _, s := range r.UrlStatus {
+		seenPages := make(map[string]bool)
+		for _, source := range s.Sources {
+			if seenPages[source.Page] {
+				continue
+			}
+			seenPages[source.Page] = true
+			groupedStatus[source.Page] = append(groupedStatus[source.Page], s)
+		}
+	}
+
+	pages := []string{}
+	for page := range groupedStatus {
+		pages = append(pages, page)
+	}
+	slices.Sort(pages)
+
+	sourceGroups := []SourceGroup{}
+	for _, page := range pages {
+		sourceGroups = append(sourceGroups, SourceGroup{Page: page, UrlStatus: groupedStatus[page]})
+	}
+	return sourceGroups
+}
+
 // Add a key-value meta date for UrlReport, does overwrite exisiting MetaData keys
 func (r UrlReport) AddMetaData(key, value string) {
 	r.MetaData[key] = value
 }
 
-// Checks if all url status are reachable, if not return false.
+// Adds counts of checked, reachable and broken urls as MetaData.
+func (r UrlReport) AddSummaryMetaData() {
+	internal, external := r.countBroken()
+	ignored := r.countIgnored()
+	skipped := r.countSkipped()
+	r.AddMetaData("total_checked_urls", fmt.Sprint(len(r.UrlStatus)))
+	r.AddMetaData("reachable_urls", fmt.Sprint(len(r.UrlStatus)-internal-external-ignored-skipped))
+	r.AddMetaData("broken_urls", fmt.Sprint(internal+external))
+	r.AddMetaData("broken_internal_references", fmt.Sprint(internal))
+	r.AddMetaData("ignored_urls", fmt.Sprint(ignored))
+	r.AddMetaData("skipped_urls", fmt.Sprint(skipped))
+}
+
+// All MetaData keys in sorted order.
+func (r UrlReport) sortedMetaDataKeys() []string {
+	keys := []string{}
+	for k := range r.MetaData {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// Checks if all url status are reachable or ignored, if not return false.
 func (r UrlReport) AllReachable() bool {
 	for _, s := range r.UrlStatus {
-		if !s.IsReachable {
+		if s.IsBroken() {
 			return false
 		}
 	}
 	return true
 }
 
+// Counts broken urls that are not ignored, split by broken internal references and broken external urls.
+func (r UrlReport) countBroken() (internal, external int) {
+	for _, s := range r.UrlStatus {
+		switch {
+		case !s.IsBroken():
+		case s.FailureCategory.IsInternal():
+			internal += 1
+		default:
+			external += 1
+		}
+	}
+	return internal, external
+}
+
+// Counts not reachable urls that are ignored by an ignore rule.
+func (r UrlReport) countIgnored() int {
+	ignored := 0
+	for _, s := range r.UrlStatus {
+		if !s.IsReachable && s.Ignored {
+			ignored += 1
+		}
+	}
+	return ignored
+}
+
+// Counts urls that were not requested because a rule skips them.
+func (r UrlReport) countSkipped() int {
+	skipped := 0
+	for _, s := range r.UrlStatus {
+		if s.Skipped {
+			skipped += 1
+		}
+	}
+	return skipped
+}
+
+// Adds all UrlStatus of other report and extends the runtime by its runtime.
+func (r UrlReport) Merge(other UrlReport) UrlReport {
+	r.UrlStatus = append(slices.Clone(r.UrlStatus), other.UrlStatus...)
+	r.Runtime += other.Runtime
+	// summary does not cover urls of other report
+	r.Summary = nil
+	return r
+}
+
 // Removed all reachable UrlStatus from report.
 func (r UrlReport) CleanupReachableUrls() UrlReport {
 	newUrlStatus := []UrlStatus{}
@@ -90,7 +248,7 @@ func CreateDryReport(urls []ExtractedUrl) UrlReport {
 	start := time.Now()
 	results := []UrlStatus{}
 	for _, e := range urls {
-		newResult := UrlStatusFromExtractedUrl(e, false, "Dry run", 0, 0)
+		newResult := UrlStatusFromExtractedUrl(e, false, dryRunStatusMessage, 0, 0)
 		results = append(results, newResult)
 	}
 	return NewUrlReport(start, time.Since(start), results)
@@ -104,6 +262,7 @@ func CreateUrlReport(urls []ExtractedUrl) UrlReport {
 // Creates UrlReport from a list of given urls with max. of parallel request routines.
 func CustomizableCreateUrlReport(urls []ExtractedUrl, maxRoutines int) UrlReport {
 	start := time.Now()
+	addQueuedUrls(len(urls))
 	inputChan := make(chan ExtractedUrl)
 	resultChan := make(chan UrlStatus)
 	urlStatus := []UrlStatus{}
@@ -128,6 +287,7 @@ func CustomizableCreateUrlReport(urls []ExtractedUrl, maxRoutines int) UrlReport
 	wg.Wait()
 	close(resultChan)
 	wg2.Wait()
+	sortByInputOrder(urlStatus, urls)
 
 	return NewUrlReport(
 		start,
@@ -141,6 +301,9 @@ func gatherResults(wg2 *sync.WaitGroup, resultChan chan UrlStatus, urlStatus *[]
 	defer wg2.Done()
 	for result := range resultChan {
 		*urlStatus = append(*urlStatus, result)
+		for _, listener := range resultListeners {
+			listener(result)
+		}
 	}
 }
 
@@ -148,7 +311,9 @@ func gatherResults(wg2 *sync.WaitGroup, resultChan chan UrlStatus, urlStatus *[]
 func checkUrlHandler(inputChan chan ExtractedUrl, resultChan chan UrlStatus, wg *sync.WaitGroup) {
 	defer wg.Done()
 	for inputUrl := range inputChan {
+		startedCheck()
 		status := UrlIsAvailable(inputUrl)
+		finishedCheck(status)
 		resultChan <- status
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -75,6 +76,20 @@ func TestCreateUrlReport(t *testing.T) {
 	})
 }
 
+func TestAddResultListener(t *testing.T) {
+	t.Cleanup(ClearResultListeners)
+	fakeServer := createDelayServerWithStatus(0*time.Second, 200)
+	defer fakeServer.Close()
+
+	received := []string{}
+	AddResultListener(func(s UrlStatus) { received = append(received, s.Url) })
+	r := CreateUrlReport([]ExtractedUrl{{Url: fakeServer.URL, NumOccured: 1}, {Url: fakeServer.URL + "/a", NumOccured: 1}})
+
+	if len(received) != 2 || len(r.UrlStatus) != 2 {
+		t.Errorf("expected listener to receive every UrlStatus, got %v", received)
+	}
+}
+
 func TestCreateDryReport(t *testing.T) {
 	t.Run("Create with one entires", func(t *testing.T) {
 		input := []ExtractedUrl{{Url: "google.de", NumOccured: 1}}
@@ -118,10 +133,10 @@ func TestAddMetaData(t *testing.T) {
 
 	t.Run("Overwrite MetaData", func(t *testing.T) {
 		report := UrlReport{
-			time.Now(),
-			time.Second,
-			map[string]string{"test2": "first set data"},
-			[]UrlStatus{},
+			ExecutedAt: time.Now(),
+			Runtime:    time.Second,
+			MetaData:   map[string]string{"test2": "first set data"},
+			UrlStatus:  []UrlStatus{},
 		}
 		key := "test2"
 		value := "new test value"
@@ -134,6 +149,28 @@ func TestAddMetaData(t *testing.T) {
 	})
 }
 
+func TestAddSummaryMetaData(t *testing.T) {
+	report := NewUrlReport(time.Now(), time.Second, []UrlStatus{
+		{IsReachable: true},
+		{IsReachable: false, FailureCategory: CategoryHttpStatus},
+		{IsReachable: false, FailureCategory: CategoryMissingAnchor},
+		{IsReachable: false, Ignored: true, FailureCategory: CategoryHttpStatus},
+		{IsReachable: false, Skipped: true},
+	})
+	report.AddSummaryMetaData()
+	want := map[string]string{
+		"total_checked_urls":         "5",
+		"reachable_urls":             "1",
+		"broken_urls":                "2",
+		"broken_internal_references": "1",
+		"ignored_urls":               "1",
+		"skipped_urls":               "1",
+	}
+	if !reflect.DeepEqual(want, report.MetaData) {
+		t.Errorf("got %v expected %v", report.MetaData, want)
+	}
+}
+
 func TestCleanupReachableUrls(t *testing.T) {
 
 	cases := []struct {
@@ -221,6 +258,43 @@ func TestReportAllReachable(t *testing.T) {
 
 }
 
+func TestGroupBySource(t *testing.T) {
+	t.Run("no sources give no groups", func(t *testing.T) {
+		r := UrlReport{UrlStatus: []UrlStatus{{Url: "http://www.google.de"}}}
+		got := r.GroupBySource()
+		if len(got) != 0 {
+			t.Errorf("expected no groups, got %v", got)
+		}
+	})
+
+	t.Run("status found on two pages is part of both groups", func(t *testing.T) {
+		google := UrlStatus{Url: "http://www.google.de", Sources: []UrlSource{{Page: "https://b.de"}, {Page: "https://a.de"}}}
+		heise := UrlStatus{Url: "http://www.heise.de", Sources: []UrlSource{{Page: "https://b.de"}}}
+		r := UrlReport{UrlStatus: []UrlStatus{google, heise}}
+		got := r.GroupBySource()
+		want := []SourceGroup{
+			{Page: "https://a.de", UrlStatus: []UrlStatus{google}},
+			{Page: "https://b.de", UrlStatus: []UrlStatus{google, heise}},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v want %v", got, want)
+		}
+	})
+}
+
+func TestFullStringGroupsBySource(t *testing.T) {
+	r := NewUrlReport(time.Now(), time.Second, []UrlStatus{
+		{Url: "http://www.google.de", Sources: []UrlSource{{Page: "https://a.de"}}},
+		{Url: "http://www.heise.de", Sources: []UrlSource{{Page: "https://b.de"}}},
+	})
+	got := r.FullString()
+	for _, want := range []string{"Source page: https://a.de\n", "Source page: https://b.de\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q to contain %q", got, want)
+		}
+	}
+}
+
 func assertReport(t *testing.T, r UrlReport, expectedUrlStatus []UrlStatus) {
 	t.Helper()
 	if len(r.UrlStatus) != len(expectedUrlStatus) {
@@ -254,3 +328,13 @@ func createDelayServerWithStatus(delay time.Duration, status int) *httptest.Serv
 
 	return fakeServer
 }
+
+func TestFullStringSortsMetaData(t *testing.T) {
+	report := NewUrlReport(time.Now(), time.Second, []UrlStatus{})
+	report.AddMetaData("c", "3")
+	report.AddMetaData("a", "1")
+	report.AddMetaData("b", "2")
+	if got := report.FullString(); !strings.Contains(got, "\ta: 1\n\tb: 2\n\tc: 3\n") {
+		t.Errorf("expected meta data in sorted order, got %q", got)
+	}
+}
@@ -0,0 +1,165 @@
+package url
+
+import (
+	"cmp"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// What the checker does with urls matching a rule.
+type RuleAction string
+
+const (
+	// Requests url and reports its status
+	ActionCheck RuleAction = ""
+	// Does not request url, it is reported as skipped
+	ActionSkip RuleAction = "skip"
+	// Requests url, it is reported as ignored if it is broken
+	ActionIgnore RuleAction = "ignore"
+)
+
+// Methods urls can be checked with
+var RequestMethods = []string{http.MethodHead, http.MethodGet}
+
+// Settings for requests to urls of a host or matching a pattern.
+type RequestRule struct {
+	// Name recorded in the UrlStatus of matching urls, host and pattern are used if empty
+	Name string
+	// Hostname of urls, *.example.com matches all subdomains, empty matches all hosts
+	Host string
+	// Regular expression urls need to match, empty matches all urls
+	Pattern string
+	// Headers sent with requests
+	Headers map[string]string
+	// Status codes besides 200 that count as reachable
+	AcceptedStatusCodes []int
+	// Overwrites HttpGetTimeout if not zero
+	Timeout time.Duration
+	// Method of the check request, HEAD if empty
+	Method string
+	// Number of repeated requests if the url is broken, nil keeps the retries of earlier rules
+	Retries *int
+	Action  RuleAction
+	regex   *regexp.Regexp
+}
+
+// Creates RequestRule for urls of host that match pattern.
+func NewRequestRule(host, pattern string) (RequestRule, error) {
+	rule := RequestRule{Host: strings.ToLower(host), Pattern: pattern}
+	if pattern != "" {
+		regex, err := regexp.Compile(pattern)
+		if err != nil {
+			return RequestRule{}, fmt.Errorf("invalid url pattern %q: %w", pattern, err)
+		}
+		rule.regex = regex
+	}
+	return rule, nil
+}
+
+// Checks if url belongs to the host and matches the pattern of the rule.
+func (r RequestRule) Matches(url string) bool {
+	if r.Host != "" {
+		host := hostOf(url)
+		subdomains, ok := strings.CutPrefix(r.Host, "*.")
+		if host != r.Host && !(ok && (host == subdomains || strings.HasSuffix(host, "."+subdomains))) {
+			return false
+		}
+	}
+	return r.regex == nil || r.regex.MatchString(url)
+}
+
+// Checks method, retries and action of the rule.
+func (r RequestRule) Validate() error {
+	if r.Method != "" && !slices.Contains(RequestMethods, r.Method) {
+		return fmt.Errorf("rule %s: unknown method %q, use one of %s", r, r.Method, strings.Join(RequestMethods, ","))
+	}
+	if r.Retries != nil && *r.Retries < 0 {
+		return fmt.Errorf("rule %s: retries need to be positive", r)
+	}
+	if r.Action != ActionCheck && r.Action != ActionSkip && r.Action != ActionIgnore {
+		return fmt.Errorf("rule %s: unknown action %q, use %s or %s", r, r.Action, ActionSkip, ActionIgnore)
+	}
+	return nil
+}
+
+// Name of the rule, or its host and pattern.
+func (r RequestRule) String() string {
+	switch {
+	case r.Name != "":
+		return r.Name
+	case r.Host != "" && r.Pattern != "":
+		return r.Host + " " + r.Pattern
+	case r.Host != "" || r.Pattern != "":
+		return r.Host + r.Pattern
+	}
+	return "*"
+}
+
+// Rules applied to every request, in order
+var requestRules = []RequestRule{}
+
+// Overwrites module wide rules for requests.
+func SetRequestRules(rules []RequestRule) {
+	requestRules = rules
+}
+
+// Combined settings of all rules matching an url.
+type requestSettings struct {
+	headers             map[string]string
+	acceptedStatusCodes []int
+	timeout             time.Duration
+	method              string
+	retries             int
+	action              RuleAction
+	// Name of the rule the action is taken from
+	actionRule string
+	// Names of the matching rules, in order
+	rules []string
+}
+
+// Combines all rules matching url, later rules overwrite headers, timeout, method, retries and action of earlier ones.
+func requestSettingsFor(url string) requestSettings {
+	settings := requestSettings{headers: map[string]string{}}
+	for _, rule := range requestRules {
+		if !rule.Matches(url) {
+			continue
+		}
+		for name, value := range rule.Headers {
+			settings.headers[name] = value
+		}
+		settings.acceptedStatusCodes = append(settings.acceptedStatusCodes, rule.AcceptedStatusCodes...)
+		if rule.Timeout > 0 {
+			settings.timeout = rule.Timeout
+		}
+		settings.method = cmp.Or(rule.Method, settings.method)
+		if rule.Retries != nil {
+			settings.retries = *rule.Retries
+		}
+		if rule.Action != ActionCheck {
+			settings.action, settings.actionRule = rule.Action, rule.String()
+		}
+		settings.rules = append(settings.rules, rule.String())
+	}
+	return settings
+}
+
+// Checks if status code counts as reachable.
+func (s requestSettings) accepts(statusCode int) bool {
+	return statusCode == http.StatusOK || slices.Contains(s.acceptedStatusCodes, statusCode)
+}
+
+// Creates request with the headers of all rules matching url.
+func newRequest(method, url string) (*http.Request, error) {
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range requestSettingsFor(url).headers {
+		req.Header.Set(name, value)
+	}
+	return req, nil
+}
@@ -0,0 +1,192 @@
+package url
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRequestRule(t *testing.T) {
+	cases := []struct {
+		host    string
+		pattern string
+		url     string
+		want    bool
+	}{
+		{"example.com", "", "https://example.com/page", true},
+		{"example.com", "", "https://EXAMPLE.com/page", true},
+		{"example.com", "", "https://www.example.com/page", false},
+		{"*.example.com", "", "https://www.example.com/page", true},
+		{"*.example.com", "", "https://example.com/page", true},
+		{"*.example.com", "", "https://badexample.com/page", false},
+		{"", `/api/`, "https://example.com/api/v1", true},
+		{"example.com", `/api/`, "https://example.com/docs", false},
+		{"", "", "https://any.de", true},
+	}
+	for _, tt := range cases {
+		rule, err := NewRequestRule(tt.host, tt.pattern)
+		if err != nil {
+			t.Fatalf("did not expect an error, got %v", err)
+		}
+		if got := rule.Matches(tt.url); got != tt.want {
+			t.Errorf("got %v for host %q pattern %q and %q expected %v", got, tt.host, tt.pattern, tt.url, tt.want)
+		}
+	}
+	if _, err := NewRequestRule("", "("); err == nil {
+		t.Error("expected an error for invalid pattern")
+	}
+}
+
+func TestRequestRulesOnChecks(t *testing.T) {
+	t.Cleanup(func() { SetRequestRules(nil) })
+	fakeServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer fakeServer.Close()
+
+	t.Run("accepted status codes", func(t *testing.T) {
+		rule, _ := NewRequestRule("", "/docs")
+		rule.AcceptedStatusCodes = []int{http.StatusUnauthorized}
+		SetRequestRules([]RequestRule{rule})
+		if got := UrlIsAvailable(ExtractedUrl{Url: fakeServer.URL + "/docs"}); !got.IsReachable {
+			t.Errorf("expected 401 to be accepted, got %v", got)
+		}
+		if got := UrlIsAvailable(ExtractedUrl{Url: fakeServer.URL + "/other"}); got.IsReachable {
+			t.Errorf("expected 401 to be broken without matching rule, got %v", got)
+		}
+	})
+	t.Run("headers", func(t *testing.T) {
+		rule, _ := NewRequestRule("127.0.0.1", "")
+		rule.Headers = map[string]string{"Authorization": "Bearer token"}
+		SetRequestRules([]RequestRule{rule})
+		if got := UrlIsAvailable(ExtractedUrl{Url: fakeServer.URL}); !got.IsReachable {
+			t.Errorf("expected header to be sent, got %v", got)
+		}
+	})
+	t.Run("later rules overwrite timeout", func(t *testing.T) {
+		first, _ := NewRequestRule("", "")
+		first.Timeout = time.Second
+		second, _ := NewRequestRule("", "/slow")
+		second.Timeout = time.Minute
+		SetRequestRules([]RequestRule{first, second})
+		if got := requestSettingsFor("https://a.de/slow").timeout; got != time.Minute {
+			t.Errorf("got timeout %v expected 1m", got)
+		}
+		if got := requestSettingsFor("https://a.de/fast").timeout; got != time.Second {
+			t.Errorf("got timeout %v expected 1s", got)
+		}
+	})
+	t.Run("later rules reset retries", func(t *testing.T) {
+		first, _ := NewRequestRule("", "")
+		first.Retries = retriesOf(3)
+		second, _ := NewRequestRule("", "/once")
+		second.Retries = retriesOf(0)
+		third, _ := NewRequestRule("", "/once")
+		SetRequestRules([]RequestRule{first, second, third})
+		if got := requestSettingsFor("https://a.de/once").retries; got != 0 {
+			t.Errorf("got retries %d expected 0", got)
+		}
+		if got := requestSettingsFor("https://a.de/other").retries; got != 3 {
+			t.Errorf("got retries %d expected 3", got)
+		}
+	})
+}
+
+// Retries of a rule, nil keeps the retries of earlier rules.
+func retriesOf(n int) *int {
+	return &n
+}
+
+func TestRequestRuleActions(t *testing.T) {
+	backoff := retryBackoff
+	retryBackoff = time.Millisecond
+	t.Cleanup(func() {
+		SetRequestRules(nil)
+		retryBackoff = backoff
+	})
+	requests := map[string]int{}
+	fakeServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path] += 1
+		switch {
+		case r.URL.Path == "/get-only" && r.Method != http.MethodGet:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		case r.URL.Path == "/flaky" && requests[r.URL.Path] < 3:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case r.URL.Path == "/broken":
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer fakeServer.Close()
+	rule := func(name, pattern string, configure func(*RequestRule)) RequestRule {
+		r, _ := NewRequestRule("", pattern)
+		r.Name = name
+		configure(&r)
+		return r
+	}
+	SetRequestRules([]RequestRule{
+		rule("get", "/get-only", func(r *RequestRule) { r.Method = http.MethodGet }),
+		rule("retry", "/flaky", func(r *RequestRule) { r.Retries = retriesOf(2) }),
+		rule("skip", "/skipped", func(r *RequestRule) { r.Action = ActionSkip }),
+		rule("ignore", "/broken", func(r *RequestRule) { r.Action = ActionIgnore }),
+	})
+
+	cases := []struct {
+		path         string
+		wantStatus   bool
+		wantIgnored  bool
+		wantSkipped  bool
+		wantRequests int
+		wantRules    []string
+	}{
+		{"/get-only", true, false, false, 1, []string{"get"}},
+		{"/flaky", true, false, false, 3, []string{"retry"}},
+		{"/skipped", false, false, true, 0, []string{"skip"}},
+		{"/broken", false, true, false, 1, []string{"ignore"}},
+		{"/other", true, false, false, 1, nil},
+	}
+	for _, tt := range cases {
+		t.Run(tt.path, func(t *testing.T) {
+			got := UrlIsAvailable(ExtractedUrl{Url: fakeServer.URL + tt.path})
+			if got.IsReachable != tt.wantStatus || got.Ignored != tt.wantIgnored || got.Skipped != tt.wantSkipped {
+				t.Errorf("got reachable %v ignored %v skipped %v expected %v %v %v",
+					got.IsReachable, got.Ignored, got.Skipped, tt.wantStatus, tt.wantIgnored, tt.wantSkipped)
+			}
+			if got.IsBroken() != (!tt.wantStatus && !tt.wantIgnored && !tt.wantSkipped) {
+				t.Errorf("got broken %v for %v", got.IsBroken(), got)
+			}
+			if requests[tt.path] != tt.wantRequests {
+				t.Errorf("got %d requests expected %d", requests[tt.path], tt.wantRequests)
+			}
+			if strings.Join(got.Rules, ",") != strings.Join(tt.wantRules, ",") {
+				t.Errorf("got rules %v expected %v", got.Rules, tt.wantRules)
+			}
+		})
+	}
+}
+
+func TestRequestRuleValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		rule    RequestRule
+		wantErr bool
+	}{
+		{"defaults", RequestRule{}, false},
+		{"all settings", RequestRule{Method: http.MethodGet, Retries: retriesOf(2), Action: ActionIgnore}, false},
+		{"unknown method", RequestRule{Method: http.MethodPost}, true},
+		{"negative retries", RequestRule{Retries: retriesOf(-1)}, true},
+		{"unknown action", RequestRule{Action: "drop"}, true},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.rule.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("got error %v, expected error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package url
+
+import (
+	"cmp"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// Orders UrlStatus of a report can be sorted by
+const (
+	SortByUrl          = "url"
+	SortByStatus       = "status"
+	SortByResponseTime = "response-time"
+	SortByOccurrences  = "occurrences"
+	SortByHost         = "host"
+)
+
+// All orders UrlStatus of a report can be sorted by
+var SortKeys = []string{SortByUrl, SortByStatus, SortByResponseTime, SortByOccurrences, SortByHost}
+
+// Sorts UrlStatus by key, ties are ordered by url. Broken urls come first when sorted by status,
+// slowest and most often found urls first when sorted by response time and occurrences.
+// Unknown keys keep the current order.
+func (r UrlReport) SortUrlStatus(key string) UrlReport {
+	var compare func(a, b UrlStatus) int
+	switch key {
+	case SortByUrl:
+		compare = func(a, b UrlStatus) int { return 0 }
+	case SortByStatus:
+		compare = func(a, b UrlStatus) int {
+			return cmp.Or(
+				compareBool(a.IsReachable, b.IsReachable),
+				cmp.Compare(a.FailureCategory, b.FailureCategory),
+				cmp.Compare(a.StatusCode, b.StatusCode),
+				cmp.Compare(a.StatusMessage, b.StatusMessage),
+			)
+		}
+	case SortByResponseTime:
+		compare = func(a, b UrlStatus) int { return cmp.Compare(b.ResponseTime, a.ResponseTime) }
+	case SortByOccurrences:
+		compare = func(a, b UrlStatus) int { return cmp.Compare(b.NumOccured, a.NumOccured) }
+	case SortByHost:
+		compare = func(a, b UrlStatus) int { return cmp.Compare(hostOf(a.Url), hostOf(b.Url)) }
+	default:
+		return r
+	}
+
+	r.UrlStatus = slices.Clone(r.UrlStatus)
+	slices.SortStableFunc(r.UrlStatus, func(a, b UrlStatus) int {
+		return cmp.Or(compare(a, b), cmp.Compare(a.Url, b.Url))
+	})
+	return r
+}
+
+// Sorts UrlStatus in the order their urls were given, independent of the order their checks finished.
+func sortByInputOrder(urlStatus []UrlStatus, urls []ExtractedUrl) {
+	positions := make(map[string]int)
+	for i, e := range urls {
+		if _, found := positions[e.Url]; !found {
+			positions[e.Url] = i
+		}
+	}
+	slices.SortStableFunc(urlStatus, func(a, b UrlStatus) int {
+		return cmp.Compare(positions[a.Url], positions[b.Url])
+	})
+}
+
+// Orders false before true.
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	}
+	return -1
+}
+
+// Lowercase host name of an url, empty if url can not be parsed.
+func hostOf(rawUrl string) string {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
@@ -0,0 +1,58 @@
+package url
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSortUrlStatus(t *testing.T) {
+	report := NewUrlReport(time.Now(), time.Second, []UrlStatus{
+		{Url: "https://b.de/page", IsReachable: true, ResponseTime: 2 * time.Second, NumOccured: 1},
+		{Url: "https://c.de", IsReachable: false, FailureCategory: CategoryTimeout, ResponseTime: 5 * time.Second, NumOccured: 3},
+		{Url: "https://a.de/z", IsReachable: false, FailureCategory: CategoryHttpStatus, StatusCode: 404, ResponseTime: time.Second, NumOccured: 1},
+		{Url: "https://a.de/a", IsReachable: true, ResponseTime: 2 * time.Second, NumOccured: 3},
+	})
+
+	cases := []struct {
+		key  string
+		want []string
+	}{
+		{SortByUrl, []string{"https://a.de/a", "https://a.de/z", "https://b.de/page", "https://c.de"}},
+		{SortByStatus, []string{"https://a.de/z", "https://c.de", "https://a.de/a", "https://b.de/page"}},
+		{SortByResponseTime, []string{"https://c.de", "https://a.de/a", "https://b.de/page", "https://a.de/z"}},
+		{SortByOccurrences, []string{"https://a.de/a", "https://c.de", "https://a.de/z", "https://b.de/page"}},
+		{SortByHost, []string{"https://a.de/a", "https://a.de/z", "https://b.de/page", "https://c.de"}},
+		{"unknown", []string{"https://b.de/page", "https://c.de", "https://a.de/z", "https://a.de/a"}},
+	}
+	for _, tt := range cases {
+		t.Run(tt.key, func(t *testing.T) {
+			got := []string{}
+			for _, s := range report.SortUrlStatus(tt.key).UrlStatus {
+				got = append(got, s.Url)
+			}
+			if !reflect.DeepEqual(tt.want, got) {
+				t.Errorf("got %v expected %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("does not change original report", func(t *testing.T) {
+		report.SortUrlStatus(SortByUrl)
+		if report.UrlStatus[0].Url != "https://b.de/page" {
+			t.Errorf("expected original order, got %s first", report.UrlStatus[0].Url)
+		}
+	})
+}
+
+func TestCreateUrlReportKeepsInputOrder(t *testing.T) {
+	slowServer := createDelayServerWithStatus(50*time.Millisecond, 200)
+	defer slowServer.Close()
+	fastServer := createDelayServerWithStatus(0, 200)
+	defer fastServer.Close()
+
+	r := CreateUrlReport([]ExtractedUrl{{Url: slowServer.URL, NumOccured: 1}, {Url: fastServer.URL, NumOccured: 1}})
+	if len(r.UrlStatus) != 2 || r.UrlStatus[0].Url != slowServer.URL {
+		t.Errorf("expected urls in input order, got %v", r.UrlStatus)
+	}
+}
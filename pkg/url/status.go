@@ -1,21 +1,49 @@
 package url
 
 import (
+	"cmp"
 	"fmt"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 )
 
 const (
 	DefaultHttpGetTimeout = 5 * time.Second
+	// Max number of redirects followed for one url
+	maxRedirects = 10
 )
 
 // time to wait for an answer of webserver
 var HttpGetTimeout = DefaultHttpGetTimeout
 
+// Reason why an url is not reachable.
+type FailureCategory string
+
+const (
+	CategoryNone FailureCategory = ""
+	// broken external urls
+	CategoryTimeout         FailureCategory = "timeout"
+	CategoryConnectionError FailureCategory = "connection_error"
+	CategoryHttpStatus      FailureCategory = "http_status"
+	// broken internal references in local documents
+	CategoryMissingFile   FailureCategory = "missing_file"
+	CategoryMissingAnchor FailureCategory = "missing_anchor"
+)
+
+// All failure categories, broken internal references first
+var FailureCategories = []FailureCategory{
+	CategoryMissingFile, CategoryMissingAnchor, CategoryHttpStatus, CategoryTimeout, CategoryConnectionError,
+}
+
+// Checks if category belongs to a broken reference inside of local documents.
+func (c FailureCategory) IsInternal() bool {
+	return c == CategoryMissingFile || c == CategoryMissingAnchor
+}
+
 // Convinience list of all files in UrlStatus
-var urlStatusHeader = []string{"url", "is_reachable", "status_message", "content_length", "response_time", "num_occured"}
+var urlStatusHeader = []string{"url", "is_reachable", "status_message", "content_length", "response_time", "num_occured", "failure_category", "ignore_reason"}
 
 // Information of a availability check on one webpage.
 type UrlStatus struct {
@@ -25,11 +53,49 @@ type UrlStatus struct {
 	ContentLength int64         `json:"content_length"`
 	ResponseTime  time.Duration `json:"response_time"`
 	NumOccured    int           `json:"num_occured"`
+	Sources       []UrlSource   `json:"sources,omitempty"`
+	ContentType   string        `json:"content_type,omitempty"`
+	// Urls the request got redirected to, in order
+	Redirects []string `json:"redirects,omitempty"`
+	// Set for links to a local site, that are checked on disk
+	LocalPath string `json:"local_path,omitempty"`
+	// Details for not reachable urls
+	StatusCode      int             `json:"status_code,omitempty"`
+	FailureCategory FailureCategory `json:"failure_category,omitempty"`
+	// Set for not reachable urls that match an ignore rule
+	Ignored      bool   `json:"ignored,omitempty"`
+	IgnoreReason string `json:"ignore_reason,omitempty"`
+	// Set for urls that were not requested because a rule skips them
+	Skipped bool `json:"skipped,omitempty"`
+	// Names of the request rules matching the url
+	Rules []string `json:"rules,omitempty"`
+	// Validators of the response for the result cache
+	etag, lastModified string
+	// Set if a conditional request confirmed the cached result
+	notModified bool
+	// Set if the result was reused from the result cache without request, its response time is not measured in this run
+	fromCache bool
+}
+
+// Checks if url is not reachable, not ignored and not skipped.
+func (s UrlStatus) IsBroken() bool {
+	return !s.IsReachable && !s.Ignored && !s.Skipped
 }
 
 // String representation of a UrlStatus.
 func (s UrlStatus) String() string {
-	return fmt.Sprintf("%s\t%v\t%s\t%d\t%s\t%d", s.Url, s.IsReachable, s.StatusMessage, s.ContentLength, s.ResponseTime, s.NumOccured)
+	return fmt.Sprintf("%s\t%v\t%s\t%d\t%s\t%d\t%s\t%s", s.Url, s.IsReachable, s.StatusMessage, s.ContentLength, s.ResponseTime, s.NumOccured, s.FailureCategory, s.IgnoreReason)
+}
+
+// All locations on a page the url was found at, as page:line. Each line is only listed once.
+func (s UrlStatus) locationsOnPage(page string) []string {
+	locations := []string{}
+	for _, source := range s.Sources {
+		if source.Page == page && !slices.Contains(locations, source.String()) {
+			locations = append(locations, source.String())
+		}
+	}
+	return locations
 }
 
 // Well formed flieds header string for UrlStatus
@@ -51,6 +117,8 @@ func UrlStatusFromExtractedUrl(e ExtractedUrl, isReachable bool, statusMessage s
 		ContentLength: contentLength,
 		ResponseTime:  responseTime,
 		NumOccured:    e.NumOccured,
+		Sources:       e.Sources,
+		LocalPath:     e.LocalPath,
 	}
 }
 
@@ -60,23 +128,85 @@ func SetHttpGetTimeoutSeconds(timeout time.Duration) {
 }
 
 // Trys a Get request on url and if status code = 200 and within timeout of HttpGetTimeout. Otherwise false.
+// Links to a local site are checked on disk. Matching request rules overwrite HttpGetTimeout, repeat requests
+// of broken urls and skip or ignore urls. Results of the result cache are reused while they are fresh.
 func UrlIsAvailable(inputUrl ExtractedUrl) (available UrlStatus) {
-	return ConfigurableUrlIsAvailable(inputUrl, HttpGetTimeout)
+	if inputUrl.LocalPath != "" {
+		return LocalFileIsAvailable(inputUrl)
+	}
+	settings := requestSettingsFor(inputUrl.Url)
+	if settings.action == ActionSkip {
+		status := UrlStatusFromExtractedUrl(inputUrl, false, "Skipped by rule "+settings.actionRule, -1, 0)
+		status.Skipped = true
+		status.Rules = settings.rules
+		return status
+	}
+	status := cachedUrlIsAvailable(inputUrl, settings)
+	status.Rules = settings.rules
+	if settings.action == ActionIgnore && !status.IsReachable {
+		status.Ignored = true
+		status.IgnoreReason = "ignored by rule " + settings.actionRule
+	}
+	return status
+}
+
+// Returns fresh result of the result cache or checks url, stale results with validators are revalidated
+// with a conditional request.
+func cachedUrlIsAvailable(inputUrl ExtractedUrl, settings requestSettings) UrlStatus {
+	timeout := cmp.Or(settings.timeout, HttpGetTimeout)
+	if resultCache == nil {
+		return retryUrlIsAvailable(inputUrl, timeout, settings.retries, nil)
+	}
+	now := time.Now()
+	entry, found, fresh := resultCache.lookup(inputUrl.Url, now)
+	if fresh {
+		return entry.statusFor(inputUrl)
+	}
+	var cached *CacheEntry
+	if found && entry.hasValidators() {
+		cached = &entry
+	}
+	status := retryUrlIsAvailable(inputUrl, timeout, settings.retries, cached)
+	if status.notModified {
+		resultCache.countRevalidated()
+	}
+	resultCache.store(status, now)
+	return status
+}
+
+// Wait before the first repeated check of a broken url, doubled for every further repetition
+var retryBackoff = 500 * time.Millisecond
+
+// Checks url and repeats the check up to retries times while it is broken, waiting longer before each repetition.
+func retryUrlIsAvailable(inputUrl ExtractedUrl, timeout time.Duration, retries int, cached *CacheEntry) UrlStatus {
+	status := conditionalUrlIsAvailable(inputUrl, timeout, cached)
+	for retry := 0; retry < retries && !status.IsReachable; retry++ {
+		time.Sleep(retryBackoff << retry)
+		status = conditionalUrlIsAvailable(inputUrl, timeout, cached)
+	}
+	return status
 }
 
 // Trys a Get request on url and if status code = 200 and within timeout returns true. Otherwise false.
 func ConfigurableUrlIsAvailable(inputUrl ExtractedUrl, timeout time.Duration) (available UrlStatus) {
+	return conditionalUrlIsAvailable(inputUrl, timeout, nil)
+}
+
+// Checks url like ConfigurableUrlIsAvailable, with validators of cached as conditional request if it is not nil.
+func conditionalUrlIsAvailable(inputUrl ExtractedUrl, timeout time.Duration, cached *CacheEntry) UrlStatus {
 	select {
-	case r := <-checkUrl(inputUrl):
+	case r := <-checkUrl(inputUrl, cached):
 		return r
 	case <-time.After(timeout):
 		return UrlStatus{
-			Url:           inputUrl.Url,
-			IsReachable:   false,
-			StatusMessage: createTimeoutMessage(timeout),
-			ContentLength: -1,
-			ResponseTime:  timeout,
-			NumOccured:    inputUrl.NumOccured,
+			Url:             inputUrl.Url,
+			IsReachable:     false,
+			StatusMessage:   createTimeoutMessage(timeout),
+			ContentLength:   -1,
+			ResponseTime:    timeout,
+			NumOccured:      inputUrl.NumOccured,
+			Sources:         inputUrl.Sources,
+			FailureCategory: CategoryTimeout,
 		}
 	}
 }
@@ -86,26 +216,80 @@ func createTimeoutMessage(timeout time.Duration) string {
 	return fmt.Sprintf("Timed out after %v", timeout)
 }
 
-// Creates chan that handels url get returns.
-func checkUrl(inputUrl ExtractedUrl) chan UrlStatus {
+// Creates http client that follows up to maxRedirects redirects and records their urls.
+func redirectRecordingClient(redirects *[]string) *http.Client {
+	return &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+			*redirects = append(*redirects, req.URL.String())
+			return nil
+		},
+	}
+}
+
+// Creates chan that handels url get returns. If cached is not nil, a not modified response returns its status.
+func checkUrl(inputUrl ExtractedUrl, cached *CacheEntry) chan UrlStatus {
 	ch := make(chan UrlStatus, 1)
 	go func() {
 		isReachable := false
 		statusMessage := "Unknown"
 		var contenLength int64
+		var statusCode int
+		var contentType string
+		category := CategoryConnectionError
 		responseTime := 0 * time.Millisecond
 		getTimerStart := time.Now()
-		resp, err := http.Head(inputUrl.Url)
+		redirects := []string{}
+		settings := requestSettingsFor(inputUrl.Url)
+		var resp *http.Response
+		req, err := newRequest(cmp.Or(settings.method, http.MethodHead), inputUrl.Url)
+		if err == nil && cached != nil {
+			if cached.ETag != "" {
+				req.Header.Set("If-None-Match", cached.ETag)
+			}
+			if cached.LastModified != "" {
+				req.Header.Set("If-Modified-Since", cached.LastModified)
+			}
+		}
+		if err == nil {
+			resp, err = redirectRecordingClient(&redirects).Do(req)
+		}
 		if err != nil {
 			statusMessage = err.Error()
 		} else {
+			resp.Body.Close()
 			responseTime = time.Since(getTimerStart)
+			if cached != nil && resp.StatusCode == http.StatusNotModified {
+				status := cached.statusFor(inputUrl)
+				status.ResponseTime = responseTime
+				status.notModified = true
+				status.fromCache = false
+				ch <- status
+				return
+			}
+			statusCode = resp.StatusCode
 			statusMessage = http.StatusText(resp.StatusCode)
-			isReachable = (resp.StatusCode == http.StatusOK)
+			isReachable = settings.accepts(resp.StatusCode)
 			contenLength = resp.ContentLength
-
+			contentType = resp.Header.Get("Content-Type")
+			category = CategoryHttpStatus
+		}
+		status := UrlStatusFromExtractedUrl(inputUrl, isReachable, statusMessage, contenLength, responseTime)
+		status.StatusCode = statusCode
+		status.ContentType = contentType
+		if resp != nil {
+			status.etag = resp.Header.Get("ETag")
+			status.lastModified = resp.Header.Get("Last-Modified")
+		}
+		if len(redirects) > 0 {
+			status.Redirects = redirects
+		}
+		if !isReachable {
+			status.FailureCategory = category
 		}
-		ch <- UrlStatusFromExtractedUrl(inputUrl, isReachable, statusMessage, contenLength, responseTime)
+		ch <- status
 
 	}()
 	return ch
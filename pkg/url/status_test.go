@@ -3,6 +3,7 @@ package url
 import (
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -16,8 +17,8 @@ func TestUrlIsAvailable(t *testing.T) {
 			w.Write([]byte("test"))
 		}))
 		defer fakeServer.Close()
-		got := UrlIsAvailable(ExtractedUrl{fakeServer.URL, 1})
-		want := UrlStatus{fakeServer.URL, true, http.StatusText(serverStatusCode), 4, time.Second, 1}
+		got := UrlIsAvailable(ExtractedUrl{Url: fakeServer.URL, NumOccured: 1})
+		want := UrlStatus{Url: fakeServer.URL, IsReachable: true, StatusMessage: http.StatusText(serverStatusCode), ContentLength: 4, ResponseTime: time.Second, NumOccured: 1}
 
 		valid, message := assertUrlStatus(want, got)
 		if !valid {
@@ -32,8 +33,8 @@ func TestUrlIsAvailable(t *testing.T) {
 			w.Write([]byte("test"))
 		}))
 		defer fakeServer.Close()
-		got := UrlIsAvailable(ExtractedUrl{fakeServer.URL, 2})
-		want := UrlStatus{fakeServer.URL, false, http.StatusText(serverStatusCode), 4, time.Second, 2}
+		got := UrlIsAvailable(ExtractedUrl{Url: fakeServer.URL, NumOccured: 2})
+		want := UrlStatus{Url: fakeServer.URL, IsReachable: false, StatusMessage: http.StatusText(serverStatusCode), ContentLength: 4, ResponseTime: time.Second, NumOccured: 2, StatusCode: 404, FailureCategory: CategoryHttpStatus}
 
 		valid, message := assertUrlStatus(want, got)
 		if !valid {
@@ -42,6 +43,29 @@ func TestUrlIsAvailable(t *testing.T) {
 
 	})
 
+	t.Run("records redirects", func(t *testing.T) {
+		fakeServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/old" {
+				http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+			}
+		}))
+		defer fakeServer.Close()
+		got := UrlIsAvailable(ExtractedUrl{Url: fakeServer.URL + "/old", NumOccured: 1})
+		want := []string{fakeServer.URL + "/new"}
+		if !got.IsReachable || !reflect.DeepEqual(got.Redirects, want) {
+			t.Errorf("expected %v to be reachable with redirects %v", got, want)
+		}
+	})
+
+	t.Run("handeling unreachable server", func(t *testing.T) {
+		fakeServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		fakeServer.Close()
+		got := UrlIsAvailable(ExtractedUrl{Url: fakeServer.URL, NumOccured: 1})
+		if got.IsReachable || got.FailureCategory != CategoryConnectionError {
+			t.Errorf("expected %v to be not reachable with category %q", got, CategoryConnectionError)
+		}
+	})
+
 	t.Run("server need more than 5ms to respond (timeout on dunction is configurable)", func(t *testing.T) {
 		serverResponseCode := 200
 		crawlerTimeout := time.Millisecond * 5
@@ -51,8 +75,8 @@ func TestUrlIsAvailable(t *testing.T) {
 		}))
 		defer fakeServer.Close()
 		SetHttpGetTimeoutSeconds(crawlerTimeout)
-		got := UrlIsAvailable(ExtractedUrl{fakeServer.URL, 3})
-		want := UrlStatus{fakeServer.URL, false, createTimeoutMessage(crawlerTimeout), -1, time.Second, 3}
+		got := UrlIsAvailable(ExtractedUrl{Url: fakeServer.URL, NumOccured: 3})
+		want := UrlStatus{Url: fakeServer.URL, IsReachable: false, StatusMessage: createTimeoutMessage(crawlerTimeout), ContentLength: -1, ResponseTime: time.Second, NumOccured: 3, FailureCategory: CategoryTimeout}
 		SetHttpGetTimeoutSeconds(DefaultHttpGetTimeout)
 		valid, message := assertUrlStatus(want, got)
 		if !valid {
@@ -66,7 +90,8 @@ func assertUrlStatus(want UrlStatus, got UrlStatus) (bool, string) {
 		want.IsReachable != got.IsReachable ||
 		want.NumOccured != got.NumOccured ||
 		want.StatusMessage != got.StatusMessage ||
-		want.ContentLength != got.ContentLength {
+		want.ContentLength != got.ContentLength ||
+		want.FailureCategory != got.FailureCategory {
 		return false, "similarity check failed"
 	}
 	if want.ResponseTime < got.ResponseTime {
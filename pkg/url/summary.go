@@ -0,0 +1,153 @@
+package url
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Number of slowest urls in the summary if nothing else is given
+const DefaultSlowestUrls = 5
+
+// Computed overview of all checked urls of a report.
+type ReportSummary struct {
+	ByStatusCode      map[int]int
+	ByFailureCategory map[FailureCategory]int
+	ByHost            []HostSummary
+	Slowest           []UrlStatus
+	ResponseTime      ResponseTimeSummary
+}
+
+// Number of checked and broken urls of one host.
+type HostSummary struct {
+	Host   string
+	Total  int
+	Broken int
+}
+
+// Distribution of response times of all urls that answered in this run, timeouts and cached results are left out.
+type ResponseTimeSummary struct {
+	Min    time.Duration
+	Median time.Duration
+	P95    time.Duration
+	Max    time.Duration
+}
+
+// Computes ReportSummary over all UrlStatus with the slowestCount slowest urls.
+// Needs to be called before reachable urls get removed from the report, to count them too.
+func (r UrlReport) Summarize(slowestCount int) UrlReport {
+	summary := ReportSummary{
+		ByStatusCode:      make(map[int]int),
+		ByFailureCategory: make(map[FailureCategory]int),
+		ByHost:            []HostSummary{},
+		Slowest:           []UrlStatus{},
+	}
+	hostPositions := make(map[string]int)
+	answered := []UrlStatus{}
+	for _, s := range r.UrlStatus {
+		if s.StatusCode != 0 {
+			summary.ByStatusCode[s.StatusCode] += 1
+		}
+		if s.IsBroken() && s.FailureCategory != CategoryNone {
+			summary.ByFailureCategory[s.FailureCategory] += 1
+		}
+
+		host := hostOf(s.Url)
+		pos, found := hostPositions[host]
+		if !found {
+			pos = len(summary.ByHost)
+			hostPositions[host] = pos
+			summary.ByHost = append(summary.ByHost, HostSummary{Host: host})
+		}
+		summary.ByHost[pos].Total += 1
+		if s.IsBroken() {
+			summary.ByHost[pos].Broken += 1
+		}
+
+		if s.ResponseTime > 0 && s.FailureCategory != CategoryTimeout && !s.fromCache {
+			answered = append(answered, s)
+		}
+	}
+	// hosts with most broken urls first
+	slices.SortFunc(summary.ByHost, func(a, b HostSummary) int {
+		return cmp.Or(cmp.Compare(b.Broken, a.Broken), cmp.Compare(b.Total, a.Total), cmp.Compare(a.Host, b.Host))
+	})
+
+	slices.SortStableFunc(answered, func(a, b UrlStatus) int {
+		return cmp.Or(cmp.Compare(b.ResponseTime, a.ResponseTime), cmp.Compare(a.Url, b.Url))
+	})
+	summary.Slowest = answered[:min(slowestCount, len(answered))]
+	if len(answered) > 0 {
+		summary.ResponseTime = ResponseTimeSummary{
+			Min:    answered[len(answered)-1].ResponseTime,
+			Median: percentileResponseTime(answered, 50),
+			P95:    percentileResponseTime(answered, 95),
+			Max:    answered[0].ResponseTime,
+		}
+	}
+
+	r.Summary = &summary
+	return r
+}
+
+// Response time at percentile by nearest rank, of UrlStatus sorted by response time descending.
+func percentileResponseTime(sortedDescending []UrlStatus, percentile int) time.Duration {
+	rank := (percentile*len(sortedDescending) + 99) / 100
+	return sortedDescending[len(sortedDescending)-max(rank, 1)].ResponseTime
+}
+
+// Text representation of ReportSummary, one line per statistic.
+func (s ReportSummary) String() string {
+	var builder strings.Builder
+	builder.WriteString("Summary:\n")
+
+	brokenHosts := []string{}
+	for _, h := range s.ByHost {
+		if h.Broken > 0 {
+			brokenHosts = append(brokenHosts, fmt.Sprintf("%s: %d of %d", h.Host, h.Broken, h.Total))
+		}
+	}
+	if len(brokenHosts) > 0 {
+		builder.WriteString("\tBroken urls by host: " + strings.Join(brokenHosts, ", ") + "\n")
+	}
+
+	if len(s.ByFailureCategory) > 0 {
+		categories := []string{}
+		for _, category := range FailureCategories {
+			if count := s.ByFailureCategory[category]; count > 0 {
+				categories = append(categories, fmt.Sprintf("%s: %d", category, count))
+			}
+		}
+		builder.WriteString("\tFailure categories: " + strings.Join(categories, ", ") + "\n")
+	}
+
+	if len(s.ByStatusCode) > 0 {
+		codes := []int{}
+		for code := range s.ByStatusCode {
+			codes = append(codes, code)
+		}
+		slices.Sort(codes)
+		statusCodes := []string{}
+		for _, code := range codes {
+			statusCodes = append(statusCodes, fmt.Sprintf("%d: %d", code, s.ByStatusCode[code]))
+		}
+		builder.WriteString("\tStatus codes: " + strings.Join(statusCodes, ", ") + "\n")
+	}
+
+	if s.ResponseTime.Max > 0 {
+		builder.WriteString(fmt.Sprintf("\tResponse time: min %s, median %s, p95 %s, max %s\n",
+			s.ResponseTime.Min.Round(time.Millisecond),
+			s.ResponseTime.Median.Round(time.Millisecond),
+			s.ResponseTime.P95.Round(time.Millisecond),
+			s.ResponseTime.Max.Round(time.Millisecond)))
+	}
+	if len(s.Slowest) > 0 {
+		builder.WriteString("\tSlowest urls:\n")
+		for _, u := range s.Slowest {
+			builder.WriteString(fmt.Sprintf("\t\t%s\t%s\n", u.ResponseTime.Round(time.Millisecond), u.Url))
+		}
+	}
+	return builder.String()
+}
@@ -0,0 +1,102 @@
+package url
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func summaryTestReport() UrlReport {
+	return NewUrlReport(time.Now(), time.Second, []UrlStatus{
+		{Url: "https://example.com/a", IsReachable: true, StatusCode: 200, ResponseTime: 100 * time.Millisecond},
+		{Url: "https://example.com/b", IsReachable: false, StatusCode: 404, FailureCategory: CategoryHttpStatus, ResponseTime: 200 * time.Millisecond},
+		{Url: "https://cdn.old-domain.com/x.js", IsReachable: false, StatusCode: 404, FailureCategory: CategoryHttpStatus, ResponseTime: 300 * time.Millisecond},
+		{Url: "https://cdn.old-domain.com/y.js", IsReachable: false, FailureCategory: CategoryConnectionError},
+		{Url: "https://cdn.old-domain.com/z.js", IsReachable: false, StatusCode: 500, FailureCategory: CategoryHttpStatus, ResponseTime: time.Second},
+	})
+}
+
+func TestSummarize(t *testing.T) {
+	summary := summaryTestReport().Summarize(2).Summary
+
+	t.Run("counts by status code and failure category", func(t *testing.T) {
+		wantCodes := map[int]int{200: 1, 404: 2, 500: 1}
+		if !reflect.DeepEqual(wantCodes, summary.ByStatusCode) {
+			t.Errorf("got %v expected %v", summary.ByStatusCode, wantCodes)
+		}
+		wantCategories := map[FailureCategory]int{CategoryHttpStatus: 3, CategoryConnectionError: 1}
+		if !reflect.DeepEqual(wantCategories, summary.ByFailureCategory) {
+			t.Errorf("got %v expected %v", summary.ByFailureCategory, wantCategories)
+		}
+	})
+	t.Run("hosts with most broken urls first", func(t *testing.T) {
+		want := []HostSummary{{Host: "cdn.old-domain.com", Total: 3, Broken: 3}, {Host: "example.com", Total: 2, Broken: 1}}
+		if !reflect.DeepEqual(want, summary.ByHost) {
+			t.Errorf("got %v expected %v", summary.ByHost, want)
+		}
+	})
+	t.Run("slowest urls", func(t *testing.T) {
+		if len(summary.Slowest) != 2 || summary.Slowest[0].Url != "https://cdn.old-domain.com/z.js" || summary.Slowest[1].Url != "https://cdn.old-domain.com/x.js" {
+			t.Errorf("got unexpected slowest urls %v", summary.Slowest)
+		}
+	})
+	t.Run("response time distribution of answered urls", func(t *testing.T) {
+		want := ResponseTimeSummary{Min: 100 * time.Millisecond, Median: 200 * time.Millisecond, P95: time.Second, Max: time.Second}
+		if want != summary.ResponseTime {
+			t.Errorf("got %v expected %v", summary.ResponseTime, want)
+		}
+	})
+	t.Run("timeouts and cached results are not timed", func(t *testing.T) {
+		report := summaryTestReport()
+		report.UrlStatus = append(report.UrlStatus,
+			UrlStatus{Url: "https://slow.de", FailureCategory: CategoryTimeout, ResponseTime: 5 * time.Second},
+			UrlStatus{Url: "https://cached.de", IsReachable: true, ResponseTime: 3 * time.Second, fromCache: true},
+		)
+		summary := report.Summarize(2).Summary
+		if summary.ResponseTime.Max != time.Second || summary.Slowest[0].Url != "https://cdn.old-domain.com/z.js" {
+			t.Errorf("got max %v and slowest %v", summary.ResponseTime.Max, summary.Slowest)
+		}
+	})
+	t.Run("empty report", func(t *testing.T) {
+		empty := NewUrlReport(time.Now(), time.Second, []UrlStatus{}).Summarize(DefaultSlowestUrls).Summary
+		if len(empty.Slowest) != 0 || empty.ResponseTime != (ResponseTimeSummary{}) {
+			t.Errorf("expected empty summary, got %v", empty)
+		}
+	})
+}
+
+func TestSummaryInOutputs(t *testing.T) {
+	report := summaryTestReport().Summarize(1)
+
+	t.Run("text output", func(t *testing.T) {
+		got := report.FullString()
+		for _, want := range []string{
+			"\tBroken urls by host: cdn.old-domain.com: 3 of 3, example.com: 1 of 2\n",
+			"\tFailure categories: http_status: 3, connection_error: 1\n",
+			"\tStatus codes: 200: 1, 404: 2, 500: 1\n",
+			"\tResponse time: min 100ms, median 200ms, p95 1s, max 1s\n",
+			"\t\t1s\thttps://cdn.old-domain.com/z.js\n",
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("expected text output to contain %q", want)
+			}
+		}
+	})
+	t.Run("json output", func(t *testing.T) {
+		got, err := report.Json()
+		if err != nil {
+			t.Fatalf("did not expect an error, got %v", err)
+		}
+		for _, want := range []string{
+			`"by_status_code":{"200":1,"404":2,"500":1}`,
+			`"by_host":[{"host":"cdn.old-domain.com","total":3,"broken":3},{"host":"example.com","total":2,"broken":1}]`,
+			`"slowest":[{"url":"https://cdn.old-domain.com/z.js","response_time":"1s"}]`,
+			`"response_time":{"min":"100ms","median":"200ms","p95":"1s","max":"1s"}`,
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("expected json output to contain %q", want)
+			}
+		}
+	})
+}
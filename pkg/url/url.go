@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"slices"
 	"strings"
 
 	"mvdan.cc/xurls/v2"
@@ -17,32 +18,30 @@ const (
 	prefixHttps = "https://"
 )
 
+// Precompiled regex to find urls with a scheme
+var strictUrlRegex = xurls.Strict()
+
 // Contains parsing info about url that is to check
 type ExtractedUrl struct {
-	Url        string
-	NumOccured int
+	Url        string      `json:"url"`
+	NumOccured int         `json:"num_occured"`
+	Sources    []UrlSource `json:"sources,omitempty"`
+	// Set for links to a local site, that are checked on disk instead of via http
+	LocalPath string `json:"local_path,omitempty"`
 }
 
-// Filter a list of ExtractedUrls with a given string excluding
-func FilterByExclude(extractedUrls []ExtractedUrl, exclude string) []ExtractedUrl {
-	resultUrls := []ExtractedUrl{}
-	for _, e := range extractedUrls {
-		if !strings.Contains(e.Url, exclude) {
-			resultUrls = append(resultUrls, e)
-		}
-	}
-	return resultUrls
+// Location where an url was found.
+type UrlSource struct {
+	Page string `json:"page"`
+	Line int    `json:"line,omitempty"`
 }
 
-// Filter a list of ExtractedUrls with a given string including
-func FilterByInclude(extractedUrls []ExtractedUrl, include string) []ExtractedUrl {
-	resultUrls := []ExtractedUrl{}
-	for _, e := range extractedUrls {
-		if strings.Contains(e.Url, include) {
-			resultUrls = append(resultUrls, e)
-		}
+// Location as page:line, or only page if line is not known.
+func (s UrlSource) String() string {
+	if s.Line == 0 {
+		return s.Page
 	}
-	return resultUrls
+	return fmt.Sprintf("%s:%d", s.Page, s.Line)
 }
 
 // Checks if given url string seems to be valid.
@@ -65,61 +64,115 @@ func IsUrlValid(inputUrl string) (isValid bool) {
 
 // Tries to recieve a body with get request from url and returns it as string.
 func GetBodyFromUrl(inputUrl string) (body string, err error) {
-	// Get request to page
-	resp, err := http.Get(inputUrl)
+	bodyBytes, _, err := GetDocumentFromUrl(inputUrl)
 	if err != nil {
 		return "", err
 	}
+	return string(bodyBytes), nil
+}
+
+// Tries to recieve a body with get request from url and returns it with its content type.
+func GetDocumentFromUrl(inputUrl string) (body []byte, contentType string, err error) {
+	// Get request to page
+	req, err := newRequest(http.MethodGet, inputUrl)
+	if err != nil {
+		return nil, "", err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
 	defer resp.Body.Close()
 	// Check return status code, if not 200 return error
 	if resp.StatusCode != http.StatusOK {
-		return "", errors.New("got status that is not okay: " + resp.Status)
+		return nil, "", errors.New("got status that is not okay: " + resp.Status)
 	}
 	// Read all data from request body
-	bodyBytes, err := io.ReadAll(resp.Body)
+	body, err = io.ReadAll(resp.Body)
 	if err != nil {
-		return "", err
+		return nil, "", err
 	}
-
-	body = string(bodyBytes)
-	return body, nil
+	return body, resp.Header.Get("Content-Type"), nil
 }
 
 // Extracts any uniqe http(s) url that can be found from a given string.
 func ExtractHttpUrls(body string) (hrefs []ExtractedUrl) {
-	precompiledUrlRegex := xurls.Strict()
-	strictUrls := precompiledUrlRegex.FindAllString(body, -1)
+	strictUrls := findStrictUrls(body)
 
 	filteredUrls := filterNoneHttpUrls(strictUrls)
 	return filteredUrls
 }
 
+// Finds all urls with a scheme in given string.
+func findStrictUrls(text string) []string {
+	return strictUrlRegex.FindAllString(text, -1)
+}
+
 // Filters down to all unique http urls
 func filterNoneHttpUrls(strictUrls []string) []ExtractedUrl {
-	httpUrls := make(map[string]int)
+	extractedUrls := []ExtractedUrl{}
+	positions := make(map[string]int)
 	for _, httpUrl := range strictUrls {
-		if strings.HasPrefix(httpUrl, "http") {
-			// convert all chars to lowercase, easy comparision
-			httpUrl = strings.ToLower(httpUrl)
-			// remove ancor sufixes, because the only point to a part of a single website
-			httpUrl = strings.SplitN(httpUrl, "#", 2)[0]
-			// relove following '/' because they are not needed
-			httpUrl = strings.TrimSuffix(httpUrl, "/")
-			httpUrls[httpUrl] += 1
+		if !strings.HasPrefix(httpUrl, "http") {
+			continue
 		}
+		normalizedUrl := normalizeUrl(httpUrl)
+		// keep order of first occurence, so results do not depend on map iteration
+		pos, found := positions[normalizedUrl]
+		if !found {
+			positions[normalizedUrl] = len(extractedUrls)
+			extractedUrls = append(extractedUrls, ExtractedUrl{Url: normalizedUrl, NumOccured: 1})
+			continue
+		}
+		extractedUrls[pos].NumOccured += 1
 	}
-	extractedUrls := []ExtractedUrl{}
-	for k, v := range httpUrls {
-		extractedUrls = append(extractedUrls, ExtractedUrl{k, v})
-	}
 
 	return extractedUrls
 }
 
+// Brings url into a form that makes it easy to compare.
+func normalizeUrl(httpUrl string) string {
+	// convert all chars to lowercase, easy comparision
+	httpUrl = strings.ToLower(httpUrl)
+	// remove ancor sufixes, because the only point to a part of a single website
+	httpUrl = strings.SplitN(httpUrl, "#", 2)[0]
+	// relove following '/' because they are not needed
+	return strings.TrimSuffix(httpUrl, "/")
+}
+
+// Extracts any uniqe http(s) url from a given string and marks the page they were found on.
+func ExtractHttpUrlsFromPage(body, sourcePage string) []ExtractedUrl {
+	extractedUrls := ExtractHttpUrls(body)
+	for i := range extractedUrls {
+		extractedUrls[i].Sources = []UrlSource{{Page: sourcePage}}
+	}
+	return extractedUrls
+}
+
+// Merges ExtractedUrls with the same url, sums up their occurences and keeps all sources.
+func MergeExtractedUrls(extractedUrls []ExtractedUrl) []ExtractedUrl {
+	mergedUrls := []ExtractedUrl{}
+	positions := make(map[string]int)
+	for _, e := range extractedUrls {
+		pos, found := positions[e.Url]
+		if !found {
+			positions[e.Url] = len(mergedUrls)
+			e.Sources = slices.Clone(e.Sources)
+			mergedUrls = append(mergedUrls, e)
+			continue
+		}
+		mergedUrls[pos].NumOccured += e.NumOccured
+		mergedUrls[pos].Sources = append(mergedUrls[pos].Sources, e.Sources...)
+	}
+	return mergedUrls
+}
+
 // Given string is checked for prefixing http(s) protocoll and gets added https if needed.
-func InferHttpsPrefix(inputUrl *string) {
+// Returns true if the prefix was added.
+func InferHttpsPrefix(inputUrl *string) bool {
 	if !strings.HasPrefix(*inputUrl, prefixHttps) && !strings.HasPrefix(*inputUrl, prefixHttp) {
-		fmt.Println("Infered https:// prefix, because given url did not have a protocol")
 		*inputUrl = "https://" + *inputUrl
+		return true
 	}
+	return false
 }
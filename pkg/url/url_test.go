@@ -69,8 +69,10 @@ func TestInferHttpsPrefix(t *testing.T) {
 
 	for _, tt := range cases {
 		t.Run(tt.name, func(t *testing.T) {
-			InferHttpsPrefix(&tt.input)
-
+			wantInferred := tt.input != tt.want
+			if inferred := InferHttpsPrefix(&tt.input); inferred != wantInferred {
+				t.Errorf("got inferred %v want %v", inferred, wantInferred)
+			}
 			if tt.input != tt.want {
 				t.Errorf("got %s want %s", tt.input, tt.want)
 			}
@@ -88,7 +90,7 @@ func TestExtractHttpUrls(t *testing.T) {
 			{
 				"basic one url in body",
 				`<html><body><a href="http://www.google.de">test</a></body></html>`,
-				[]ExtractedUrl{{"http://www.google.de", 1}},
+				[]ExtractedUrl{{Url: "http://www.google.de", NumOccured: 1}},
 			}, {
 				"No urls found",
 				`<html><body><a href="google.de">test</a></body></html>`,
@@ -97,8 +99,8 @@ func TestExtractHttpUrls(t *testing.T) {
 				"two unique urls found",
 				`<html><body><a href="google.de">https://heise.de http://www.google.de</a></body></html>`,
 				[]ExtractedUrl{
-					{"https://heise.de", 1},
-					{"http://www.google.de", 1},
+					{Url: "https://heise.de", NumOccured: 1},
+					{Url: "http://www.google.de", NumOccured: 1},
 				},
 			}, {
 				"Only non http urls",
@@ -107,19 +109,19 @@ func TestExtractHttpUrls(t *testing.T) {
 			}, {
 				"Remove doubled urls",
 				`<html><body><a href="http://www.google.de">http://www.google.de</a></body></html>`,
-				[]ExtractedUrl{{"http://www.google.de", 2}},
+				[]ExtractedUrl{{Url: "http://www.google.de", NumOccured: 2}},
 			}, {
 				"Lowercase and uppercase have to be ignored, cast everything to lowercast",
 				`<html><body><a href="http://www.GOOGLE.de">http://www.google.de</a></body></html>`,
-				[]ExtractedUrl{{"http://www.google.de", 2}},
+				[]ExtractedUrl{{Url: "http://www.google.de", NumOccured: 2}},
 			}, {
 				"Cut ancor tags on links",
 				`<html><body><a href="http://www.google.de/#very-good-link">http://www.google.de/</a></body></html>`,
-				[]ExtractedUrl{{"http://www.google.de", 2}},
+				[]ExtractedUrl{{Url: "http://www.google.de", NumOccured: 2}},
 			}, {
 				"Remove tailing / on urls",
 				`<html><body><a href="http://www.google.de/">hello</a></body></html>`,
-				[]ExtractedUrl{{"http://www.google.de", 1}},
+				[]ExtractedUrl{{Url: "http://www.google.de", NumOccured: 1}},
 			},
 		}
 		for _, tt := range cases {
@@ -130,7 +132,7 @@ func TestExtractHttpUrls(t *testing.T) {
 					t.Fatalf("expected %v and %v to have the same length", tt.want, got)
 				}
 				for _, wantElement := range tt.want {
-					if !slices.Contains(got, wantElement) {
+					if !slices.ContainsFunc(got, func(e ExtractedUrl) bool { return reflect.DeepEqual(e, wantElement) }) {
 						t.Errorf("expected %v to include want %v", got, wantElement)
 					}
 				}
@@ -139,6 +141,19 @@ func TestExtractHttpUrls(t *testing.T) {
 
 	})
 
+	t.Run("keeps order of first occurence", func(t *testing.T) {
+		body := `https://c.de https://a.de https://c.de https://b.de`
+		want := []ExtractedUrl{
+			{Url: "https://c.de", NumOccured: 2},
+			{Url: "https://a.de", NumOccured: 1},
+			{Url: "https://b.de", NumOccured: 1},
+		}
+		for i := 0; i < 10; i++ {
+			if got := ExtractHttpUrls(body); !reflect.DeepEqual(want, got) {
+				t.Fatalf("got %v expected %v", got, want)
+			}
+		}
+	})
 }
 
 func TestIsUrlValid(t *testing.T) {
@@ -201,11 +216,27 @@ func TestIsUrlValid(t *testing.T) {
 	}
 }
 
+// Keeps urls allowed by a filter built from includes and excludes.
+func filterUrls(t *testing.T, extractedUrls []ExtractedUrl, includes, excludes []string) []ExtractedUrl {
+	t.Helper()
+	filter, err := NewUrlFilter(includes, excludes, false)
+	if err != nil {
+		t.Fatalf("did not expect an error, got %v", err)
+	}
+	resultUrls := []ExtractedUrl{}
+	for _, e := range extractedUrls {
+		if allowed, _ := filter.Allows(e.Url); allowed {
+			resultUrls = append(resultUrls, e)
+		}
+	}
+	return resultUrls
+}
+
 func TestFilterByExclude(t *testing.T) {
 
 	t.Run("exclude on an empty list", func(t *testing.T) {
 		input := []ExtractedUrl{}
-		got := FilterByExclude(input, "google")
+		got := filterUrls(t, input, nil, []string{"google"})
 		want := []ExtractedUrl{}
 		if !reflect.DeepEqual(got, want) {
 			t.Errorf("got %v, want %v", got, want)
@@ -214,7 +245,7 @@ func TestFilterByExclude(t *testing.T) {
 
 	t.Run("exclude on a list with one entry that needs to excludes", func(t *testing.T) {
 		input := []ExtractedUrl{{Url: "www.google.de", NumOccured: 99}}
-		got := FilterByExclude(input, "google")
+		got := filterUrls(t, input, nil, []string{"google"})
 		want := []ExtractedUrl{}
 		if !reflect.DeepEqual(got, want) {
 			t.Errorf("got %v, want %v", got, want)
@@ -223,7 +254,7 @@ func TestFilterByExclude(t *testing.T) {
 
 	t.Run("exclude on a list with one entry that needs to stay after exclude", func(t *testing.T) {
 		input := []ExtractedUrl{{Url: "www.google.de", NumOccured: 99}}
-		got := FilterByExclude(input, "heise")
+		got := filterUrls(t, input, nil, []string{"heise"})
 		want := []ExtractedUrl{{Url: "www.google.de", NumOccured: 99}}
 		if !reflect.DeepEqual(got, want) {
 			t.Errorf("got %v, want %v", got, want)
@@ -231,7 +262,7 @@ func TestFilterByExclude(t *testing.T) {
 	})
 	t.Run("exclude on a list with two entry that needs to excludes", func(t *testing.T) {
 		input := []ExtractedUrl{{Url: "http://www.google.de", NumOccured: 99}, {Url: "http://www.google.de/help", NumOccured: 99}}
-		got := FilterByExclude(input, "google")
+		got := filterUrls(t, input, nil, []string{"google"})
 		want := []ExtractedUrl{}
 		if !reflect.DeepEqual(got, want) {
 			t.Errorf("got %v, want %v", got, want)
@@ -240,7 +271,7 @@ func TestFilterByExclude(t *testing.T) {
 
 	t.Run("exclude on a list with two entries one needs to stay after exclude", func(t *testing.T) {
 		input := []ExtractedUrl{{Url: "www.google.de", NumOccured: 99}, {Url: "www.heise.de", NumOccured: 9}}
-		got := FilterByExclude(input, "heise")
+		got := filterUrls(t, input, nil, []string{"heise"})
 		want := []ExtractedUrl{{Url: "www.google.de", NumOccured: 99}}
 		if !reflect.DeepEqual(got, want) {
 			t.Errorf("got %v, want %v", got, want)
@@ -252,7 +283,7 @@ func TestFilterByInclude(t *testing.T) {
 
 	t.Run("include on an empty list", func(t *testing.T) {
 		input := []ExtractedUrl{}
-		got := FilterByInclude(input, "google")
+		got := filterUrls(t, input, []string{"google"}, nil)
 		want := []ExtractedUrl{}
 		if !reflect.DeepEqual(got, want) {
 			t.Errorf("got %v, want %v", got, want)
@@ -261,7 +292,7 @@ func TestFilterByInclude(t *testing.T) {
 
 	t.Run("include on a list with one entry that needs to be excluded", func(t *testing.T) {
 		input := []ExtractedUrl{{Url: "www.google.de", NumOccured: 99}}
-		got := FilterByInclude(input, "heise")
+		got := filterUrls(t, input, []string{"heise"}, nil)
 		want := []ExtractedUrl{}
 		if !reflect.DeepEqual(got, want) {
 			t.Errorf("got %v, want %v", got, want)
@@ -270,7 +301,7 @@ func TestFilterByInclude(t *testing.T) {
 
 	t.Run("include on a list with one entry that needs to stay after inclusion", func(t *testing.T) {
 		input := []ExtractedUrl{{Url: "www.google.de", NumOccured: 99}}
-		got := FilterByInclude(input, "google")
+		got := filterUrls(t, input, []string{"google"}, nil)
 		want := []ExtractedUrl{{Url: "www.google.de", NumOccured: 99}}
 		if !reflect.DeepEqual(got, want) {
 			t.Errorf("got %v, want %v", got, want)
@@ -278,7 +309,7 @@ func TestFilterByInclude(t *testing.T) {
 	})
 	t.Run("include on a list with two entry that needs to be included", func(t *testing.T) {
 		input := []ExtractedUrl{{Url: "http://www.google.de", NumOccured: 99}, {Url: "http://www.google.de/help", NumOccured: 99}}
-		got := FilterByInclude(input, "google")
+		got := filterUrls(t, input, []string{"google"}, nil)
 		want := []ExtractedUrl{{Url: "http://www.google.de", NumOccured: 99}, {Url: "http://www.google.de/help", NumOccured: 99}}
 		if !reflect.DeepEqual(got, want) {
 			t.Errorf("got %v, want %v", got, want)
@@ -287,10 +318,71 @@ func TestFilterByInclude(t *testing.T) {
 
 	t.Run("include on a list with two entries one needs to stay", func(t *testing.T) {
 		input := []ExtractedUrl{{Url: "www.google.de", NumOccured: 99}, {Url: "www.heise.de", NumOccured: 9}}
-		got := FilterByInclude(input, "google")
+		got := filterUrls(t, input, []string{"google"}, nil)
 		want := []ExtractedUrl{{Url: "www.google.de", NumOccured: 99}}
 		if !reflect.DeepEqual(got, want) {
 			t.Errorf("got %v, want %v", got, want)
 		}
 	})
 }
+
+func TestExtractHttpUrlsFromPage(t *testing.T) {
+	body := `<html><body><a href="http://www.google.de">test</a></body></html>`
+	got := ExtractHttpUrlsFromPage(body, "https://www.heise.de")
+	want := []ExtractedUrl{{Url: "http://www.google.de", NumOccured: 1, Sources: []UrlSource{{Page: "https://www.heise.de"}}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestMergeExtractedUrls(t *testing.T) {
+	t.Run("merge on an empty list", func(t *testing.T) {
+		got := MergeExtractedUrls([]ExtractedUrl{})
+		want := []ExtractedUrl{}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("same url from two pages gets merged", func(t *testing.T) {
+		input := []ExtractedUrl{
+			{Url: "http://www.google.de", NumOccured: 2, Sources: []UrlSource{{Page: "https://a.de"}}},
+			{Url: "http://www.heise.de", NumOccured: 1, Sources: []UrlSource{{Page: "https://a.de"}}},
+			{Url: "http://www.google.de", NumOccured: 3, Sources: []UrlSource{{Page: "https://b.de"}}},
+		}
+		got := MergeExtractedUrls(input)
+		want := []ExtractedUrl{
+			{Url: "http://www.google.de", NumOccured: 5, Sources: []UrlSource{{Page: "https://a.de"}, {Page: "https://b.de"}}},
+			{Url: "http://www.heise.de", NumOccured: 1, Sources: []UrlSource{{Page: "https://a.de"}}},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("merging does not modify input sources", func(t *testing.T) {
+		input := []ExtractedUrl{
+			{Url: "http://www.google.de", NumOccured: 1, Sources: []UrlSource{{Page: "https://a.de"}}},
+			{Url: "http://www.google.de", NumOccured: 1, Sources: []UrlSource{{Page: "https://b.de"}}},
+		}
+		MergeExtractedUrls(input)
+		if len(input[0].Sources) != 1 {
+			t.Errorf("expected input sources to be untouched, got %v", input[0].Sources)
+		}
+	})
+}
+
+func TestUrlSourceString(t *testing.T) {
+	cases := []struct {
+		source UrlSource
+		want   string
+	}{
+		{UrlSource{Page: "https://www.google.de"}, "https://www.google.de"},
+		{UrlSource{Page: "docs/README.md", Line: 12}, "docs/README.md:12"},
+	}
+	for _, tt := range cases {
+		if got := tt.source.String(); got != tt.want {
+			t.Errorf("got %q want %q", got, tt.want)
+		}
+	}
+}